@@ -7,8 +7,10 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/MercerMorning/go_example/auth/internal/client/db"
 	"github.com/MercerMorning/go_example/auth/internal/config"
 	"github.com/MercerMorning/go_example/auth/internal/monitoring"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
 	"github.com/getsentry/sentry-go"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -19,14 +21,14 @@ func main() {
 	fmt.Println("=== Enterprise Sentry Integration Example ===")
 
 	// Загружаем конфигурацию
-	err := config.Load(".env")
+	cfg, err := config.NewLoader(nil).Load()
 	if err != nil {
 		fmt.Printf("Failed to load config: %v\n", err)
 		return
 	}
 
 	// Создаем enterprise конфигурацию Sentry
-	sentryConfig := monitoring.LoadSentryConfigFromEnv()
+	sentryConfig := monitoring.LoadSentryConfig(cfg.Sentry)
 	if sentryConfig == nil {
 		fmt.Println("Sentry is disabled (no DSN provided)")
 		return
@@ -41,8 +43,8 @@ func main() {
 	}
 	defer sentryEnterprise.Flush()
 
-	// Создаем middleware
-	middleware := monitoring.NewSentryMiddleware(sentryEnterprise)
+	// Создаем middleware поверх Reporter Sentry
+	middleware := monitoring.NewMiddleware(reporter.NewSentry(), monitoring.LoadThresholds(cfg.Sentry.SlowThreshold))
 
 	// Демонстрируем различные сценарии
 	demonstrateEnterpriseFeatures(sentryEnterprise, middleware)
@@ -50,12 +52,13 @@ func main() {
 	fmt.Println("\n=== Enterprise example completed ===")
 }
 
-func demonstrateEnterpriseFeatures(enterprise *monitoring.SentryEnterprise, middleware *monitoring.SentryMiddleware) {
+func demonstrateEnterpriseFeatures(enterprise *monitoring.SentryEnterprise, middleware *monitoring.Middleware) {
 	ctx := context.Background()
 
 	// 1. Бизнес-события
 	fmt.Println("\n1. Business Events:")
 	enterprise.CaptureBusinessEvent(
+		ctx,
 		"user_registration",
 		"user_123",
 		map[string]interface{}{
@@ -73,6 +76,7 @@ func demonstrateEnterpriseFeatures(enterprise *monitoring.SentryEnterprise, midd
 	duration := time.Since(start)
 
 	enterprise.CapturePerformanceIssue(
+		ctx,
 		"user_creation",
 		duration,
 		100*time.Millisecond, // threshold
@@ -143,22 +147,17 @@ func demonstrateEnterpriseFeatures(enterprise *monitoring.SentryEnterprise, midd
 	fmt.Println("\n5. Database Middleware:")
 	dbMiddleware := middleware.DatabaseMiddleware()
 
-	// Симуляция быстрого запроса
-	dbMiddleware(func() error {
-		time.Sleep(50 * time.Millisecond)
-		return nil
-	})
+	// Хук вызывается клиентом БД после каждого запроса
+	query := db.Query{Name: "user_repository.Create", QueryRaw: "INSERT INTO users ..."}
 
-	// Симуляция медленного запроса
-	dbMiddleware(func() error {
-		time.Sleep(1500 * time.Millisecond) // Медленный запрос
-		return nil
-	})
+	// Быстрый запрос
+	dbMiddleware(ctx, query, 50*time.Millisecond, nil)
 
-	// Симуляция ошибки БД
-	dbMiddleware(func() error {
-		return errors.New("database connection timeout")
-	})
+	// Медленный запрос
+	dbMiddleware(ctx, query, 1500*time.Millisecond, nil)
+
+	// Ошибка БД
+	dbMiddleware(ctx, query, 10*time.Millisecond, errors.New("database connection timeout"))
 
 	// 6. Business logic middleware
 	fmt.Println("\n6. Business Logic Middleware:")
@@ -8,21 +8,21 @@ import (
 
 	"github.com/MercerMorning/go_example/auth/internal/config"
 	"github.com/MercerMorning/go_example/auth/internal/monitoring"
-	"github.com/getsentry/sentry-go"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
 )
 
 func main() {
 	fmt.Println("=== HTTP Context Auto-Population Example ===")
 
 	// Загружаем конфигурацию
-	err := config.Load(".env")
+	cfg, err := config.NewLoader(nil).Load()
 	if err != nil {
 		fmt.Printf("Failed to load config: %v\n", err)
 		return
 	}
 
 	// Создаем enterprise конфигурацию Sentry
-	sentryConfig := monitoring.LoadSentryConfigFromEnv()
+	sentryConfig := monitoring.LoadSentryConfig(cfg.Sentry)
 	if sentryConfig == nil {
 		fmt.Println("Sentry is disabled (no DSN provided)")
 		return
@@ -37,8 +37,9 @@ func main() {
 	}
 	defer sentryEnterprise.Flush()
 
-	// Создаем middleware
-	middleware := monitoring.NewSentryMiddleware(sentryEnterprise)
+	// Создаем middleware поверх Reporter Sentry, обработчики отправляют ошибки через него же
+	reporter.Set(reporter.NewSentry())
+	middleware := monitoring.NewMiddleware(reporter.Get(), monitoring.LoadThresholds(cfg.Sentry.SlowThreshold))
 
 	// Создаем HTTP сервер с Sentry middleware
 	mux := http.NewServeMux()
@@ -89,9 +90,9 @@ func usersHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Printf("Processing request: %s %s\n", r.Method, r.URL.Path)
 
 	// Логируем информацию о запросе (будет отправлено в Sentry как breadcrumb)
-	sentry.AddBreadcrumb(&sentry.Breadcrumb{
+	reporter.AddBreadcrumb(r.Context(), reporter.Breadcrumb{
 		Message:   "Processing users request",
-		Level:     sentry.LevelInfo,
+		Level:     reporter.LevelInfo,
 		Timestamp: time.Now(),
 		Data: map[string]interface{}{
 			"endpoint": "/api/users",
@@ -118,9 +119,9 @@ func ordersHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Логируем бизнес-событие
-	sentry.AddBreadcrumb(&sentry.Breadcrumb{
+	reporter.AddBreadcrumb(r.Context(), reporter.Breadcrumb{
 		Message:   "User accessed orders",
-		Level:     sentry.LevelInfo,
+		Level:     reporter.LevelInfo,
 		Timestamp: time.Now(),
 		Data: map[string]interface{}{
 			"user_id":  userID,
@@ -144,12 +145,15 @@ func errorHandler(w http.ResponseWriter, r *http.Request) {
 	err := errors.New("database connection failed")
 
 	// Отправляем ошибку в Sentry с контекстом HTTP запроса
-	monitoring.CaptureHTTPError(r, err, map[string]string{
-		"component": "error_handler",
-		"operation": "process_request",
-	}, map[string]interface{}{
-		"request_id": r.Header.Get("X-Request-ID"),
-		"timestamp":  time.Now(),
+	reporter.CaptureError(r.Context(), err, reporter.Event{
+		Tags: map[string]string{
+			"component": "error_handler",
+			"operation": "process_request",
+		},
+		Extra: map[string]interface{}{
+			"request_id": r.Header.Get("X-Request-ID"),
+			"timestamp":  time.Now(),
+		},
 	})
 
 	w.Header().Set("Content-Type", "application/json")
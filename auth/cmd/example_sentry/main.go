@@ -8,36 +8,28 @@ import (
 
 	"github.com/MercerMorning/go_example/auth/internal/config"
 	"github.com/MercerMorning/go_example/auth/internal/logger"
-	"github.com/getsentry/sentry-go"
+	"github.com/MercerMorning/go_example/auth/internal/monitoring"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 func main() {
 	// Загружаем конфигурацию
-	err := config.Load(".env")
+	cfg, err := config.NewLoader(nil).Load()
 	if err != nil {
 		fmt.Printf("Failed to load config: %v\n", err)
 		return
 	}
 
-	// Инициализируем Sentry
-	sentryConfig := config.NewSentryConfig()
-	if sentryConfig.IsEnabled() {
-		err = logger.InitSentry(
-			sentryConfig.DSN,
-			sentryConfig.Environment,
-			sentryConfig.Release,
-			sentryConfig.Debug,
-			sentryConfig.SampleRate,
-			sentryConfig.TracesSampleRate,
-		)
-		if err != nil {
-			fmt.Printf("Failed to init Sentry: %v\n", err)
-			return
-		}
-		defer logger.FlushSentry(2 * time.Second)
+	// Инициализируем Reporter: Sentry, файл или память по секции error_reporting
+	r, closeReporter, err := monitoring.NewReporter(cfg.Reporting, cfg.Sentry)
+	if err != nil {
+		fmt.Printf("Failed to init error reporter: %v\n", err)
+		return
 	}
+	defer func() { _ = closeReporter(context.Background()) }()
+	reporter.Set(r)
 
 	// Инициализируем логгер с Sentry интеграцией
 	zapLog, err := zap.NewProduction()
@@ -46,13 +38,11 @@ func main() {
 		return
 	}
 
-	var core zapcore.Core = zapLog.Core()
-	if sentryConfig.IsEnabled() {
-		sentryCore := logger.NewSentryCore(zapLog.Core(), zapcore.ErrorLevel)
-		core = sentryCore
-	}
-
-	logger.Init(core)
+	// Ошибки лога уходят в Reporter, записи ниже logger.sentry.level — breadcrumbs.
+	// Sync отправляет очередь до закрытия Reporter.
+	sentryCore := logger.NewSentryCore(cfg.Logger.Sentry)
+	logger.Init(zapcore.NewTee(zapLog.Core(), sentryCore))
+	defer func() { _ = sentryCore.Sync() }()
 
 	// Примеры использования
 	demonstrateSentryFeatures()
@@ -88,36 +78,39 @@ func demonstrateSentryFeatures() {
 
 	// 5. Прямая отправка ошибки в Sentry
 	customError := errors.New("custom business logic error")
-	logger.CaptureError(customError, map[string]string{
-		"component": "user_service",
-		"operation": "create_user",
-	}, map[string]interface{}{
-		"user_id": "12345",
-		"email":   "user@example.com",
+	reporter.CaptureError(context.Background(), customError, reporter.Event{
+		Tags: map[string]string{
+			"component": "user_service",
+			"operation": "create_user",
+		},
+		Extra: map[string]interface{}{
+			"user_id": "12345",
+			"email":   "user@example.com",
+		},
 	})
 
 	// 6. Прямая отправка сообщения в Sentry
-	logger.CaptureMessage("Important business event occurred",
-		sentry.LevelInfo,
-		map[string]string{
+	reporter.CaptureMessage(context.Background(), "Important business event occurred", reporter.Event{
+		Level: reporter.LevelInfo,
+		Tags: map[string]string{
 			"event_type": "user_registration",
-		}, map[string]interface{}{
+		},
+		Extra: map[string]interface{}{
 			"user_id": "67890",
 			"plan":    "premium",
-		})
+		},
+	})
 
 	// 7. Создание транзакции для отслеживания производительности
-	ctx := context.Background()
-	transaction := logger.StartTransaction(ctx, "user_creation", "user.create")
-	defer transaction.Finish()
+	ctx, transaction := reporter.StartTransaction(context.Background(), "user_creation", "user.create")
+	defer transaction.Finish(nil)
 
 	// Симуляция работы
 	time.Sleep(100 * time.Millisecond)
 
-	// Создание span внутри транзакции
-	span := transaction.StartChild("database.insert")
+	// Шаги операции записываются как breadcrumbs транзакции
+	reporter.AddBreadcrumb(ctx, reporter.Breadcrumb{Category: "db", Message: "user inserted"})
 	time.Sleep(50 * time.Millisecond)
-	span.Finish()
 
 	// 8. Фатальная ошибка (будет отправлена в Sentry)
 	logger.Fatal("Critical system failure",
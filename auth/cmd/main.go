@@ -4,18 +4,33 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
 	"github.com/MercerMorning/go_example/auth/internal/app"
+	"github.com/MercerMorning/go_example/auth/internal/config"
 	"github.com/MercerMorning/go_example/auth/internal/logger"
 )
 
-var logLevel = flag.String("l", "info", "log level")
-
 func main() {
+	loader := config.NewLoader(flag.CommandLine)
 	flag.Parse()
 
+	cfg, err := loader.Load()
+	if err != nil {
+		log.Fatalf("invalid configuration:\n%s", err.Error())
+	}
+
+	if loader.PrintConfig() {
+		out, err := cfg.MaskedYAML()
+		if err != nil {
+			log.Fatalf("failed to print config: %s", err.Error())
+		}
+		_, _ = os.Stdout.Write(out)
+		return
+	}
+
 	ctx := context.Background()
-	a, err := app.NewApp(ctx)
+	a, err := app.NewApp(ctx, loader, cfg)
 	if err != nil {
 		log.Fatalf("failed to init app: %s", err.Error())
 	}
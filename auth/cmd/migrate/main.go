@@ -1,75 +1,249 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/MercerMorning/go_example/auth/internal/client/db/migrate"
 	"github.com/MercerMorning/go_example/auth/internal/config"
 )
 
+const usage = `Usage: migrate [flags] <command> [args]
+
+Commands:
+  up             apply all pending migrations
+  down           roll back all migrations (asks for confirmation)
+  steps N        apply N migrations (N > 0) or roll back -N migrations (N < 0)
+  goto V         migrate up or down to version V
+  force V        set version V without running migrations
+  version        print current version
+  status         list applied and pending migrations
+  create NAME    create a pair of up/down migration files
+  lint           check migration files for risky operations
+  verify         apply every migration up, down and up again on a scratch
+                 database and compare the resulting schemas
+
+Flags:
+`
+
 func main() {
 	var (
-		command = flag.String("command", "", "Migration command: up, down, force, version")
-		version = flag.Int("version", 0, "Version for force command")
+		dsn            = flag.String("dsn", "", "PostgreSQL DSN (default: PG_DSN environment variable)")
+		migrationsPath = flag.String("path", "migrations", "Path to the migrations directory")
+		dryRun         = flag.Bool("dry-run", false, "Print SQL that would be executed without running it")
+		yes            = flag.Bool("yes", false, "Do not ask for confirmation of destructive commands")
+		command        = flag.String("command", "", "Migration command (deprecated: pass the command as an argument)")
+		version        = flag.Int("version", 0, "Version for force command (deprecated: pass the version as an argument)")
 	)
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	if *command == "" {
-		log.Fatal("command is required. Use: up, down, force, version")
+	args := flag.Args()
+	if *command != "" {
+		args = append([]string{*command}, args...)
+		if *command == "force" && *version != 0 {
+			args = append(args, strconv.Itoa(*version))
+		}
+	}
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
 	}
 
-	// Get database configuration
-	pgConfig, err := config.NewPGConfig()
-	if err != nil {
-		log.Fatalf("Failed to create PG config: %v", err)
+	cmd, cmdArgs := args[0], args[1:]
+
+	// create и lint не требуют подключения к БД
+	if cmd == "lint" {
+		issues, err := migrate.Lint(*migrationsPath)
+		if err != nil {
+			log.Fatalf("Failed to lint migrations: %v", err)
+		}
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		if len(issues) > 0 {
+			log.Fatalf("Found %d issue(s). Fix them or add '-- migrate-lint:allow <rule>' before the statement", len(issues))
+		}
+		fmt.Println("No issues found")
+		return
 	}
 
-	// Get migrations path
-	wd, err := os.Getwd()
-	if err != nil {
-		log.Fatalf("Failed to get working directory: %v", err)
+	if cmd == "create" {
+		if len(cmdArgs) != 1 {
+			log.Fatal("create requires a migration name")
+		}
+		upFile, downFile, err := migrate.Create(*migrationsPath, cmdArgs[0], time.Now())
+		if err != nil {
+			log.Fatalf("Failed to create migration: %v", err)
+		}
+		fmt.Printf("Created migration files: %s and %s\n", upFile, downFile)
+		return
 	}
-	migrationsPath := filepath.Join(wd, "..", "..", "migrations")
 
-	// Create migrator
-	migrator, err := migrate.NewMigrator(pgConfig.DSN(), migrationsPath)
+	if *dsn == "" {
+		cfg, err := config.NewLoader(nil).Load()
+		if err != nil {
+			log.Fatalf("DSN is required: pass -dsn or set PG_DSN: %v", err)
+		}
+		*dsn = cfg.PGConfig().DSN()
+	}
+
+	migrator, err := migrate.NewMigrator(*dsn, *migrationsPath)
 	if err != nil {
 		log.Fatalf("Failed to create migrator: %v", err)
 	}
 	defer migrator.Close()
 
-	// Execute command
-	switch *command {
+	if err = run(migrator, cmd, cmdArgs, *dryRun, *yes); err != nil {
+		migrator.Close()
+		log.Fatal(err)
+	}
+}
+
+func run(migrator *migrate.Migrator, cmd string, args []string, dryRun, yes bool) error {
+	switch cmd {
 	case "up":
-		if err := migrator.Up(); err != nil {
-			log.Fatalf("Failed to run migrations up: %v", err)
+		if dryRun {
+			return printPlan(migrator.PlanUp())
 		}
+		return migrator.Up()
 	case "down":
-		if err := migrator.Down(); err != nil {
-			log.Fatalf("Failed to run migrations down: %v", err)
+		if dryRun {
+			return printPlan(migrator.PlanDown())
 		}
-	case "force":
-		if *version == 0 {
-			log.Fatal("version is required for force command")
+		if !yes && !confirm("This will roll back ALL migrations and drop the whole schema.") {
+			return fmt.Errorf("aborted")
+		}
+		return migrator.Down()
+	case "steps":
+		n, err := intArg(args, "steps")
+		if err != nil {
+			return err
 		}
-		if err := migrator.Force(*version); err != nil {
-			log.Fatalf("Failed to force migration version: %v", err)
+		if dryRun {
+			return printPlan(migrator.PlanSteps(n))
 		}
+		return migrator.Steps(n)
+	case "goto":
+		v, err := intArg(args, "goto")
+		if err != nil {
+			return err
+		}
+		if v < 0 {
+			return fmt.Errorf("goto requires a non-negative version")
+		}
+		if dryRun {
+			return printPlan(migrator.PlanGoto(uint(v)))
+		}
+		return migrator.Goto(uint(v))
+	case "force":
+		v, err := intArg(args, "force")
+		if err != nil {
+			return err
+		}
+		return migrator.Force(v)
 	case "version":
 		version, dirty, err := migrator.Version()
 		if err != nil {
-			log.Fatalf("Failed to get migration version: %v", err)
+			return err
 		}
 		if dirty {
 			fmt.Printf("Current version: %d (dirty)\n", version)
 		} else {
 			fmt.Printf("Current version: %d\n", version)
 		}
+		return nil
+	case "status":
+		return printStatus(migrator)
+	case "verify":
+		if err := migrator.Verify(context.Background()); err != nil {
+			return err
+		}
+		fmt.Println("All migrations are reversible")
+		return nil
 	default:
-		log.Fatalf("Unknown command: %s. Use: up, down, force, version", *command)
+		return fmt.Errorf("unknown command: %s. Run with -h for usage", cmd)
 	}
 }
+
+func intArg(args []string, cmd string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%s requires exactly one numeric argument", cmd)
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid number %q", cmd, args[0])
+	}
+
+	return n, nil
+}
+
+func printStatus(migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No migrations found")
+		return nil
+	}
+
+	fmt.Printf("%-8s %-16s %-20s %s\n", "STATUS", "VERSION", "CREATED AT", "NAME")
+	for _, s := range statuses {
+		state := "pending"
+		if s.Applied {
+			state = "applied"
+		}
+
+		createdAt := "-"
+		if !s.CreatedAt.IsZero() {
+			createdAt = s.CreatedAt.Format("2006-01-02 15:04:05")
+		}
+
+		fmt.Printf("%-8s %-16d %-20s %s\n", state, s.Version, createdAt, s.Name)
+	}
+
+	return nil
+}
+
+func printPlan(planned []migrate.PlannedMigration, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if len(planned) == 0 {
+		fmt.Println("-- nothing to do")
+		return nil
+	}
+
+	for _, p := range planned {
+		fmt.Printf("-- %s %d_%s\n", strings.ToUpper(string(p.Direction)), p.Version, p.Name)
+		fmt.Println(strings.TrimSpace(p.SQL))
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func confirm(warning string) bool {
+	fmt.Printf("%s\nType 'yes' to continue: ", warning)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(answer) == "yes"
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
@@ -18,6 +19,7 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/MercerMorning/go_example/auth/internal/config"
 	"github.com/MercerMorning/go_example/auth/internal/interceptor"
 	"github.com/MercerMorning/go_example/auth/internal/logger"
 	"github.com/MercerMorning/go_example/auth/internal/tracing"
@@ -112,9 +114,19 @@ func main() {
 
 	logger.Init(getCore(getAtomicLevel()))
 
-	// Create a simple logger for tracing
-	zapLogger, _ := zap.NewProduction()
-	tracing.Init(zapLogger, serviceName)
+	shutdownTracing, err := tracing.Init(context.Background(), config.Tracing{
+		ServiceName:    serviceName,
+		ServiceVersion: "dev",
+		Environment:    "development",
+		Exporter:       "otlp",
+		OTLPEndpoint:   "localhost:4317",
+		OTLPInsecure:   true,
+		SampleRatio:    1,
+	})
+	if err != nil {
+		log.Fatalf("failed to init tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcPort))
 	if err != nil {
@@ -122,6 +134,7 @@ func main() {
 	}
 
 	s := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 		grpc.UnaryInterceptor(
 			interceptor.ServerTracingInterceptor,
 		),
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/MercerMorning/go_example/auth/internal/client/db/pg"
+	"github.com/MercerMorning/go_example/auth/internal/client/db/transaction"
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/seed"
+
+	userRepository "github.com/MercerMorning/go_example/auth/internal/repository/user"
+	userService "github.com/MercerMorning/go_example/auth/internal/service/user"
+)
+
+func main() {
+	var (
+		dsn      = flag.String("dsn", "", "PostgreSQL DSN (default: PG_DSN environment variable)")
+		dir      = flag.String("dir", "fixtures", "Path to the fixtures directory")
+		env      = flag.String("env", "local", "Fixture set to load on top of fixtures/base")
+		files    = flag.Bool("files", false, "Treat arguments as fixture files to load instead of a fixture set")
+		generate = flag.Int("generate", 0, "Number of synthetic users to generate for load testing")
+		password = flag.String("password", "loadtest-password", "Password for generated users")
+		workers  = flag.Int("workers", 4, "Number of concurrent workers for generated users")
+	)
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if *dsn == "" {
+		cfg, err := config.NewLoader(nil).Load()
+		if err != nil {
+			log.Fatalf("DSN is required: pass -dsn or set PG_DSN: %v", err)
+		}
+		*dsn = cfg.PGConfig().DSN()
+	}
+
+	dbClient, err := pg.New(ctx, *dsn)
+	if err != nil {
+		log.Fatalf("Failed to create db client: %v", err)
+	}
+	defer dbClient.Close()
+
+	users := userService.NewService(
+		userRepository.NewRepository(dbClient),
+		transaction.NewTransactionManager(dbClient.DB()),
+	)
+	userSeeder := seed.NewUserSeeder(users)
+
+	seeder := seed.New()
+	seeder.Register("users", userSeeder)
+
+	if *files {
+		_, err = seeder.LoadFiles(ctx, flag.Args()...)
+	} else {
+		_, err = seeder.LoadEnv(ctx, *dir, *env)
+	}
+	if err != nil {
+		dbClient.Close()
+		log.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	if *generate > 0 {
+		var stats seed.Stats
+		stats, err = userSeeder.Generate(ctx, *generate, *workers, *password)
+		log.Printf("Synthetic users: created=%d skipped=%d", stats.Created, stats.Skipped)
+		if err != nil {
+			dbClient.Close()
+			log.Fatalf("Failed to generate users: %v", err)
+		}
+	}
+
+	log.Println("Seeding completed successfully")
+}
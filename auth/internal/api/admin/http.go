@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/MercerMorning/go_example/auth/internal/interceptor"
+	desc "github.com/MercerMorning/go_example/auth/pkg/admin_v1"
+)
+
+const maxRequestBodySize = 1 << 10
+
+// LogLevelHandler HTTP версия GetLogLevel (GET) и SetLogLevel (PUT).
+// Токен проверяется тем же авторизатором, что и в gRPC.
+func (i *Implementation) LogLevelHandler(authorizer *interceptor.Authorizer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if p, ok := authorizer.Authenticate(r.Header.Get("Authorization")); ok {
+			ctx = interceptor.ContextWithPrincipal(ctx, p)
+		}
+
+		var (
+			resp *desc.LogLevelResponse
+			err  error
+		)
+		switch r.Method {
+		case http.MethodGet:
+			resp, err = i.GetLogLevel(ctx, &desc.GetLogLevelRequest{})
+		case http.MethodPut:
+			req := &desc.SetLogLevelRequest{}
+			body, readErr := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+			if readErr == nil {
+				readErr = protojson.Unmarshal(body, req)
+			}
+			if readErr != nil {
+				http.Error(w, "invalid request body: "+readErr.Error(), http.StatusBadRequest)
+				return
+			}
+			resp, err = i.SetLogLevel(ctx, req)
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err != nil {
+			st := status.Convert(err)
+			http.Error(w, st.Message(), runtime.HTTPStatusFromCode(st.Code()))
+			return
+		}
+
+		writeJSON(w, resp)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, msg proto.Message) {
+	body, err := protojson.Marshal(msg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
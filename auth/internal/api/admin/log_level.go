@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/converter"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	desc "github.com/MercerMorning/go_example/auth/pkg/admin_v1"
+)
+
+func (i *Implementation) GetLogLevel(ctx context.Context, _ *desc.GetLogLevelRequest) (*desc.LogLevelResponse, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return converter.ToLogLevelResponseFromLevels(logger.Level().String(), logger.SubsystemLevels()), nil
+}
+
+func (i *Implementation) SetLogLevel(ctx context.Context, req *desc.SetLogLevelRequest) (*desc.LogLevelResponse, error) {
+	p, err := requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetSubsystem() == "" {
+		// Пустой уровень сбрасывает только подсистему: zap прочитал бы его как info
+		if req.GetLevel() == "" {
+			return nil, status.Error(codes.InvalidArgument, "level is required for the global log level")
+		}
+		err = logger.SetLevel(req.GetLevel())
+	} else if req.GetLevel() == "" {
+		err = logger.ResetSubsystemLevel(req.GetSubsystem())
+	} else {
+		err = logger.SetSubsystemLevel(req.GetSubsystem(), req.GetLevel())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to set log level: %v", err)
+	}
+
+	logger.Info("Log level changed",
+		zap.String("subsystem", req.GetSubsystem()),
+		zap.String("level", req.GetLevel()),
+		zap.String("by", p.Subject),
+	)
+
+	return converter.ToLogLevelResponseFromLevels(logger.Level().String(), logger.SubsystemLevels()), nil
+}
@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/interceptor"
+	desc "github.com/MercerMorning/go_example/auth/pkg/admin_v1"
+)
+
+// AdminRole роль токена, которому доступны служебные методы
+const AdminRole = "admin"
+
+type Implementation struct {
+	desc.UnimplementedAdminV1Server
+}
+
+func NewImplementation() *Implementation {
+	return &Implementation{}
+}
+
+func requireAdmin(ctx context.Context) (interceptor.Principal, error) {
+	p, ok := interceptor.PrincipalFromContext(ctx)
+	if !ok {
+		return p, status.Error(codes.Unauthenticated, "valid bearer token is required")
+	}
+	if p.Role != AdminRole {
+		return p, status.Errorf(codes.PermissionDenied, "role %q is not allowed to use admin API", p.Role)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/MercerMorning/go_example/auth/internal/api/admin"
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/interceptor"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	desc "github.com/MercerMorning/go_example/auth/pkg/admin_v1"
+)
+
+func TestLogLevelHandler(t *testing.T) {
+	logger.Init(zapcore.NewNopCore())
+	t.Cleanup(func() {
+		require.NoError(t, logger.SetLevel("info"))
+		require.NoError(t, logger.SetSubsystemLevel(logger.SubsystemDB, ""))
+	})
+
+	authorizer := interceptor.NewAuthorizer(config.Auth{
+		Tokens: []config.Token{
+			{Token: "admin-token", Subject: "ops", Role: admin.AdminRole},
+			{Token: "user-token", Subject: "alice", Role: "user"},
+		},
+	})
+	handler := admin.NewImplementation().LogLevelHandler(authorizer)
+
+	do := func(method, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/admin/log-level", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "", "").Code)
+	require.Equal(t, http.StatusForbidden, do(http.MethodGet, "user-token", "").Code)
+
+	rec := do(http.MethodPut, "admin-token", `{"subsystem": "db", "level": "debug"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, logger.Named(logger.SubsystemDB).Core().Enabled(zapcore.DebugLevel))
+	require.False(t, logger.Get().Core().Enabled(zapcore.DebugLevel))
+
+	rec = do(http.MethodGet, "admin-token", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	resp := &desc.LogLevelResponse{}
+	require.NoError(t, protojson.Unmarshal(rec.Body.Bytes(), resp))
+	require.Equal(t, "info", resp.GetLevel())
+	require.Equal(t, "debug", resp.GetSubsystems()[logger.SubsystemDB].GetLevel())
+	require.True(t, resp.GetSubsystems()[logger.SubsystemGRPC].GetInherited())
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "admin-token", `{"level": "loud"}`).Code)
+	require.NoError(t, logger.SetLevel("warn"))
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "admin-token", `{}`).Code)
+	require.Equal(t, "warn", logger.Level().String())
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "admin-token", `{"subsystem": "cache", "level": "debug"}`).Code)
+}
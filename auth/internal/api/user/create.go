@@ -2,9 +2,11 @@ package user
 
 import (
 	"context"
-	"log"
+
+	"go.uber.org/zap"
 
 	"github.com/MercerMorning/go_example/auth/internal/converter"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
 	desc "github.com/MercerMorning/go_example/auth/pkg/user_v1"
 )
 
@@ -28,11 +30,11 @@ func (i *Implementation) Create(ctx context.Context, req *desc.CreateRequest) (*
 
 	otherServiceResp, err := i.userClient.Create(ctx, otherServiceReq)
 	if err != nil {
-		log.Printf("Failed to create user in other_service: %v", err)
+		logger.FromContext(ctx).Warn("Failed to create user in other_service", zap.Error(err))
 		// Возвращаем успешный ответ от локального сервиса, даже если other_service не сработал
 		// В реальном приложении здесь может быть другая логика обработки ошибок
 	} else {
-		log.Printf("Successfully created user in other_service with ID: %d", otherServiceResp.GetId())
+		logger.FromContext(ctx).Info("Successfully created user in other_service", zap.Int64("other_service_id", otherServiceResp.GetId()))
 	}
 
 	return converter.ToCreateResponseFromID(id), nil
@@ -2,11 +2,13 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/gojuno/minimock/v3"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 
 	"github.com/MercerMorning/go_example/auth/internal/api/user"
 	"github.com/MercerMorning/go_example/auth/internal/model"
@@ -64,6 +66,7 @@ func TestCreate(t *testing.T) {
 		want            *desc.CreateResponse
 		err             error
 		userServiceMock userServiceMockFunc
+		userClient      desc.UserV1Client
 	}{
 		{
 			name: "success case",
@@ -78,6 +81,23 @@ func TestCreate(t *testing.T) {
 				mock.CreateMock.Expect(ctx, info).Return(id, nil)
 				return mock
 			},
+			userClient: fakeUserClient{},
+		},
+		{
+			// Пользователь уже создан локально, ошибка other_service только пишется в лог
+			name: "other_service error",
+			args: args{
+				ctx: ctx,
+				req: req,
+			},
+			want: res,
+			err:  nil,
+			userServiceMock: func(mc *minimock.Controller) service.UserService {
+				mock := serviceMocks.NewUserServiceMock(mc)
+				mock.CreateMock.Expect(ctx, info).Return(id, nil)
+				return mock
+			},
+			userClient: fakeUserClient{err: errors.New("other_service is unavailable")},
 		},
 	}
 
@@ -87,7 +107,7 @@ func TestCreate(t *testing.T) {
 			// t.Parallel()
 
 			userServiceMock := tt.userServiceMock(mc)
-			api := user.NewImplementation(userServiceMock)
+			api := user.NewImplementation(userServiceMock, tt.userClient)
 
 			got, err := api.Create(tt.args.ctx, tt.args.req)
 
@@ -96,3 +116,16 @@ func TestCreate(t *testing.T) {
 		})
 	}
 }
+
+// fakeUserClient клиент other_service, Create которого возвращает err
+type fakeUserClient struct {
+	desc.UserV1Client
+	err error
+}
+
+func (c fakeUserClient) Create(context.Context, *desc.CreateRequest, ...grpc.CallOption) (*desc.CreateResponse, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &desc.CreateResponse{Id: 1}, nil
+}
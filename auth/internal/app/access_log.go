@@ -0,0 +1,34 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/MercerMorning/go_example/auth/internal/httpstatus"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/tlsconfig"
+)
+
+// accessLogHandler пишет HTTP запросы в логгер подсистемы http на уровне debug
+func accessLogHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		rec := httpstatus.NewRecorder(w)
+
+		next.ServeHTTP(rec, r)
+
+		fields := []zap.Field{
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", rec.Status),
+			zap.Duration("duration", time.Since(now)),
+		}
+		if id, ok := tlsconfig.RequestIdentity(r); ok {
+			fields = append(fields, zap.String(logger.PeerKey, id.String()))
+		}
+
+		logger.NamedFromContext(r.Context(), logger.SubsystemHTTP).Debug("request", fields...)
+	})
+}
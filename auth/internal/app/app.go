@@ -2,45 +2,67 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
-	"sync"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/MercerMorning/go_example/auth/internal/closer"
 	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/gateway"
+	"github.com/MercerMorning/go_example/auth/internal/health"
 	"github.com/MercerMorning/go_example/auth/internal/interceptor"
 	"github.com/MercerMorning/go_example/auth/internal/metric"
+	"github.com/MercerMorning/go_example/auth/internal/monitoring"
+	"github.com/MercerMorning/go_example/auth/internal/redact"
+	"github.com/MercerMorning/go_example/auth/internal/reload"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+	"github.com/MercerMorning/go_example/auth/internal/supervisor"
+	"github.com/MercerMorning/go_example/auth/internal/swagger"
 	"github.com/MercerMorning/go_example/auth/internal/tracing"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
-	"github.com/grpc-ecosystem/grpc-opentracing/go/otgrpc"
 	"github.com/natefinch/lumberjack"
-	"github.com/opentracing/opentracing-go"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/rs/cors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 
+	adminDesc "github.com/MercerMorning/go_example/auth/pkg/admin_v1"
 	desc "github.com/MercerMorning/go_example/auth/pkg/user_v1"
 
 	"github.com/MercerMorning/go_example/auth/internal/logger"
-	grpcMiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
 )
 
 type App struct {
+	config          *config.Config
+	reloader        *reload.Reloader
+	authorizer      *interceptor.Authorizer
+	health          *health.Registry
+	options         []Option
 	serviceProvider *serviceProvider
 	grpcServer      *grpc.Server
 	httpServer      *http.Server
+	metricsServer   *http.Server
+	swaggerServer   *http.Server
 }
 
-func NewApp(ctx context.Context) (*App, error) {
-	a := &App{}
+// NewApp создает зависимости и серверы. Опции подменяют зависимости контейнера.
+func NewApp(ctx context.Context, loader *config.Loader, cfg *config.Config, opts ...Option) (*App, error) {
+	a := &App{
+		config:   cfg,
+		reloader: reload.New(loader, cfg),
+		options:  opts,
+	}
+
+	closer.SetTimeout(cfg.Shutdown.CloserTimeout)
 
 	err := a.initDeps(ctx)
 	if err != nil {
@@ -50,107 +72,131 @@ func NewApp(ctx context.Context) (*App, error) {
 	return a, nil
 }
 
+// Run запускает серверы и фоновые задачи и блокируется до SIGINT/SIGTERM
+// или ошибки любого из них, после чего завершает работу сервиса.
+// Возвращает первую ошибку.
 func (a *App) Run() error {
-	defer func() {
-		// Flush Sentry перед завершением
-		logger.FlushSentry(2 * time.Second)
-		closer.CloseAll()
-		closer.Wait()
-	}()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	wg := sync.WaitGroup{}
-	wg.Add(2)
-
-	go func() {
-		defer wg.Done()
-		defer logger.RecoverPanicSilent() // Перехватываем паники в горутинах
+	sv, ctx := supervisor.New(ctx)
 
-		err := a.runGRPCServer()
-		if err != nil {
-			logger.Error("Failed to run GRPC server",
-				zap.String("error", err.Error()))
-			log.Fatalf("failed to run GRPC server: %v", err)
-		}
-	}()
+	sv.Go("grpc_server", func(context.Context) error {
+		return a.runGRPCServer()
+	})
+	sv.Go("http_server", func(context.Context) error {
+		return a.runHTTPServer()
+	})
+	sv.Go("metrics_server", func(context.Context) error {
+		return a.runMetric()
+	})
+	if a.swaggerServer != nil {
+		sv.Go("swagger_server", func(context.Context) error {
+			return a.runSwaggerServer()
+		})
+	}
+	sv.Go("config_reloader", func(ctx context.Context) error {
+		a.reloader.Run(ctx, a.config.Reload.Interval)
+		return nil
+	})
+	sv.Go("health", func(ctx context.Context) error {
+		a.health.Run(ctx)
+		return nil
+	})
 
-	go func() {
-		defer wg.Done()
-		defer logger.RecoverPanicSilent() // Перехватываем паники в горутинах
+	// Завершение запускается по сигналу или после ошибки любого компонента
+	sv.Go("shutdown", func(ctx context.Context) error {
+		<-ctx.Done()
+		stop()
 
-		err := a.runHTTPServer()
-		if err != nil {
-			logger.Error("Failed to run HTTP server",
-				zap.String("error", err.Error()))
-			log.Fatalf("failed to run HTTP server: %v", err)
-		}
-	}()
+		return a.shutdown()
+	})
 
-	go func() {
-		defer wg.Done()
-		defer logger.RecoverPanicSilent() // Перехватываем паники в горутинах
+	return sv.Wait()
+}
 
-		err := a.runMetric()
-		if err != nil {
-			logger.Error("Failed to run METRIC server",
-				zap.String("error", err.Error()))
-			log.Fatalf("failed to run METRIC server: %v", err)
-		}
-	}()
+// shutdown переводит сервис в NOT_SERVING и закрывает ресурсы по фазам:
+// серверы, фоновые задачи, исходящие клиенты, БД. Повторный сигнал завершает процесс сразу.
+func (a *App) shutdown() error {
+	logger.Info("Shutting down", zap.Duration("timeout", a.config.Shutdown.Timeout))
 
-	// go func() {
-	// 	defer wg.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), a.config.Shutdown.Timeout)
+	defer cancel()
 
-	// 	err := a.runSwaggerServer()
-	// 	if err != nil {
-	// 		log.Fatalf("failed to run Swagger server: %v", err)
-	// 	}
-	// }()
+	a.health.Drain()
+	if delay := a.config.Shutdown.DrainDelay; delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
 
-	wg.Wait()
+	err := closer.Shutdown(ctx)
+	if err != nil {
+		logger.Error("Shutdown finished with errors", zap.Error(err))
+	} else {
+		logger.Info("Shutdown finished")
+	}
 
-	return nil
+	return err
 }
 
 func (a *App) runMetric() error {
-	mux := http.NewServeMux()
-	mux.Handle("/metrics", promhttp.Handler())
+	log.Printf("Prometheus server is running on %s", a.config.Metrics.Address)
 
-	prometheusServer := &http.Server{
-		Addr:    "localhost:2112",
-		Handler: mux,
+	err := listenAndServe(a.metricsServer)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
 
-	log.Printf("Prometheus server is running on %s", "localhost:2112")
+	return nil
+}
 
-	err := prometheusServer.ListenAndServe()
-	if err != nil {
+func (a *App) runHTTPServer() error {
+	log.Printf("HTTP server is running on %s", a.serviceProvider.HTTPConfig().Address())
+
+	err := listenAndServe(a.httpServer)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 
 	return nil
 }
 
-func (a *App) runHTTPServer() error {
-	log.Printf("HTTP server is running on %s", a.serviceProvider.HTTPConfig().Address())
+func (a *App) runSwaggerServer() error {
+	log.Printf("Swagger server is running on %s", a.config.Swagger.Address())
 
-	err := a.httpServer.ListenAndServe()
-	if err != nil {
+	err := listenAndServe(a.swaggerServer)
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 
 	return nil
 }
 
+// listenAndServe запускает HTTP сервер, с TLS — если задан TLSConfig.
+// Сертификат берется из TLSConfig, поэтому пути к файлам не передаются.
+func listenAndServe(server *http.Server) error {
+	if server.TLSConfig != nil {
+		return server.ListenAndServeTLS("", "")
+	}
+
+	return server.ListenAndServe()
+}
+
 func (a *App) initDeps(ctx context.Context) error {
 	inits := []func(context.Context) error{
-		a.initConfig,
-		a.initSentry,
-		a.initMonitoring,
 		a.initLogger,
+		a.initTracing,
 		a.initServiceProvider,
+		a.initMonitoring,
+		a.initAuthorizer,
+		a.initHealth,
 		a.initGRPCServer,
 		a.initHTTPServer,
+		a.initSwaggerServer,
 		a.initMetric,
+		a.initHealthChecks,
 	}
 
 	for _, f := range inits {
@@ -160,112 +206,176 @@ func (a *App) initDeps(ctx context.Context) error {
 		}
 	}
 
+	logger.Debug("Dependencies initialized", zap.String("graph", a.DependencyGraph()))
+
 	return nil
 }
 
-func (a *App) initMetric(ctx context.Context) error {
-	err := metric.Init(ctx)
+// DependencyGraph возвращает граф созданных зависимостей для отладки
+func (a *App) DependencyGraph() string {
+	return a.serviceProvider.DependencyGraph()
+}
+
+func (a *App) initMetric(_ context.Context) error {
+	err := metric.Init(a.config.Metrics)
 	if err != nil {
 		return err
 	}
+
+	tlsConfig, err := a.serviceProvider.Certificates().Server(a.config.Metrics.TLS)
+	if err != nil {
+		return fmt.Errorf("failed to configure TLS for metrics server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metric.Handler())
+
+	a.metricsServer = &http.Server{
+		Addr:      a.config.Metrics.Address,
+		Handler:   mux,
+		TLSConfig: tlsConfig,
+	}
+	closer.Register(closer.PhaseServers, "metrics_server", a.metricsServer.Shutdown)
+
 	return nil
 }
 
 func (a *App) initHTTPServer(ctx context.Context) error {
-	mux := runtime.NewServeMux()
+	mux := gateway.NewServeMux(
+		runtime.WithMiddlewares(metric.GatewayMiddleware, tracing.GatewayMiddleware),
+	)
+
+	creds, err := clientCredentials(a.serviceProvider.Certificates(), a.config.Gateway.TLS)
+	if err != nil {
+		return fmt.Errorf("failed to configure TLS for gateway: %w", err)
+	}
 
 	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
 	}
 
-	err := desc.RegisterUserV1HandlerFromEndpoint(ctx, mux, a.serviceProvider.GRPCConfig().Address(), opts)
+	err = desc.RegisterUserV1HandlerFromEndpoint(ctx, mux, a.serviceProvider.GRPCConfig().Address(), opts)
 	if err != nil {
 		return err
 	}
 
-	corsMiddleware := cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Content-Type", "Content-Length", "Authorization"},
-		AllowCredentials: true,
-	})
+	httpMux := http.NewServeMux()
+	httpMux.Handle("/healthz", a.health.LivenessHandler())
+	httpMux.Handle("/readyz", a.health.ReadinessHandler())
+	httpMux.Handle("/admin/log-level", a.serviceProvider.AdminImpl().LogLevelHandler(a.authorizer))
+	httpMux.Handle("/", mux)
+
+	if a.config.Swagger.Enabled && a.config.Swagger.Port == "" {
+		docs, err := swagger.Handler(a.config.Swagger, "", "")
+		if err != nil {
+			return err
+		}
+		httpMux.Handle(swagger.Prefix, docs)
+	}
+
+	corsMiddleware := newCORSHandler(metric.HTTPMiddleware(httpMux), a.config.CORS)
+	err = a.reloader.Subscribe("cors", corsMiddleware.Reload)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := a.serviceProvider.Certificates().Server(a.config.HTTP.TLS)
+	if err != nil {
+		return fmt.Errorf("failed to configure TLS for HTTP server: %w", err)
+	}
+
+	mon, err := a.serviceProvider.Monitoring()
+	if err != nil {
+		return err
+	}
+
+	// Monitoring снаружи recoveryHandler: паника перехватывается внутри и отправляется
+	// в scope запроса один раз
+	handler := gateway.MaxBodySize(corsMiddleware, a.config.HTTPSecurity.MaxBodySize)
+	handler = mon.HTTPMiddleware()(securityHeaders(recoveryHandler(handler), a.config.HTTPSecurity))
 
 	a.httpServer = &http.Server{
-		Addr:    a.serviceProvider.HTTPConfig().Address(),
-		Handler: corsMiddleware.Handler(mux),
+		Addr:      a.serviceProvider.HTTPConfig().Address(),
+		Handler:   tracing.HTTPMiddleware(requestid.HTTPMiddleware(accessLogHandler(handler))),
+		TLSConfig: tlsConfig,
 	}
+	closer.Register(closer.PhaseServers, "http_server", a.httpServer.Shutdown)
 
 	return nil
 }
 
-func (a *App) initMonitoring(_ context.Context) error {
-	sentryConfig := config.NewSentryConfig()
-
-	if sentryConfig.IsEnabled() {
-		err := logger.InitSentry(
-			sentryConfig.DSN,
-			sentryConfig.Environment,
-			sentryConfig.Release,
-			sentryConfig.Debug,
-			sentryConfig.SampleRate,
-			sentryConfig.TracesSampleRate,
-		)
-		if err != nil {
-			return err
-		}
+// initSwaggerServer создает отдельный сервер документации, если задан swagger.port.
+// В документе указывается адрес HTTP сервера, так как запросы к API идут туда.
+func (a *App) initSwaggerServer(_ context.Context) error {
+	if !a.config.Swagger.Enabled || a.config.Swagger.Port == "" {
+		return nil
 	}
 
-	return nil
-}
+	scheme := "http"
+	if a.config.HTTP.TLS.Enabled {
+		scheme = "https"
+	}
 
-func (a *App) initSentry(_ context.Context) error {
-	sentryConfig := config.NewSentryConfig()
-
-	if sentryConfig.IsEnabled() {
-		err := logger.InitSentry(
-			sentryConfig.DSN,
-			sentryConfig.Environment,
-			sentryConfig.Release,
-			sentryConfig.Debug,
-			sentryConfig.SampleRate,
-			sentryConfig.TracesSampleRate,
-		)
-		if err != nil {
-			return err
-		}
+	docs, err := swagger.Handler(a.config.Swagger, a.serviceProvider.HTTPConfig().Address(), scheme)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := a.serviceProvider.Certificates().Server(a.config.HTTP.TLS)
+	if err != nil {
+		return fmt.Errorf("failed to configure TLS for Swagger server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(swagger.Prefix, docs)
+	mux.Handle("/{$}", http.RedirectHandler(swagger.Prefix, http.StatusFound))
+
+	a.swaggerServer = &http.Server{
+		Addr:      a.config.Swagger.Address(),
+		Handler:   accessLogHandler(securityHeaders(mux, a.config.HTTPSecurity)),
+		TLSConfig: tlsConfig,
 	}
+	closer.Register(closer.PhaseServers, "swagger_server", a.swaggerServer.Shutdown)
 
 	return nil
 }
 
-func (a *App) initLogger(_ context.Context) error {
-	_, err := zap.NewProduction()
+// initMonitoring создает Reporter до создания серверов и клиента БД, которые подключают
+// его middleware, делает его Reporter по умолчанию для логгера и recovery и применяет
+// новые доли событий Sentry при перезагрузке
+func (a *App) initMonitoring(_ context.Context) error {
+	r, err := a.serviceProvider.Reporter()
 	if err != nil {
 		return err
 	}
+	reporter.Set(r)
 
-	// Создаем Sentry core если Sentry включен
-	sentryConfig := config.NewSentryConfig()
-	// var core zapcore.Core = zapLog.Core()
+	return a.reloader.Subscribe("sentry", monitoring.Reload)
+}
 
-	if sentryConfig.IsEnabled() {
-		// sentryCore := logger.NewSentryCore(zapLog.Core(), zapcore.ErrorLevel)
-		// core = sentryCore
-	}
+func (a *App) initLogger(_ context.Context) error {
+	redact.Init(a.config.Redaction)
 
-	logger.Init(getCore(getAtomicLevel()))
+	// Уровни общего логгера и подсистем фильтруются в пакете logger
+	core := getCore(zapcore.DebugLevel, a.config.Logger)
+	if a.config.Logger.Sentry.Enabled {
+		// Reporter задается позже, в initMonitoring: SentryCore берет его при отправке
+		core = zapcore.NewTee(core, logger.NewSentryCore(a.config.Logger.Sentry))
+	}
+	logger.Init(core)
 
-	return nil
+	return a.reloader.Subscribe("logger", logger.Reload)
 }
 
-func getCore(level zap.AtomicLevel) zapcore.Core {
+func getCore(level zapcore.LevelEnabler, cfg config.Logger) zapcore.Core {
 	stdout := zapcore.AddSync(os.Stdout)
 
 	file := zapcore.AddSync(&lumberjack.Logger{
-		Filename:   "logs/app.log",
-		MaxSize:    10, // megabytes
-		MaxBackups: 3,
-		MaxAge:     7, // days
+		Filename:   cfg.FilePath,
+		MaxSize:    cfg.MaxSizeMB,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAgeDays,
 	})
 
 	productionCfg := zap.NewProductionEncoderConfig()
@@ -284,50 +394,141 @@ func getCore(level zap.AtomicLevel) zapcore.Core {
 	)
 }
 
-func getAtomicLevel() zap.AtomicLevel {
-	var level zapcore.Level
-
-	return zap.NewAtomicLevelAt(level)
-}
-
-func (a *App) initConfig(_ context.Context) error {
-	err := config.Load(".env")
+func (a *App) initTracing(ctx context.Context) error {
+	shutdown, err := tracing.Init(ctx, a.config.Tracing)
 	if err != nil {
 		return err
 	}
+	closer.Register(closer.PhaseTelemetry, "tracing", shutdown)
 
 	return nil
 }
 
 func (a *App) initServiceProvider(_ context.Context) error {
-	a.serviceProvider = newServiceProvider()
+	a.serviceProvider = newServiceProvider(a.config, a.options...)
+
+	// SIGHUP перечитывает и сертификаты, не дожидаясь проверки при новом соединении
+	return a.reloader.Subscribe("certificates", a.serviceProvider.Certificates().Reload)
+}
+
+func (a *App) initAuthorizer(_ context.Context) error {
+	a.authorizer = interceptor.NewAuthorizer(a.config.Auth)
+	return a.reloader.Subscribe("authorizer", a.authorizer.Reload)
+}
+
+func (a *App) initHealth(_ context.Context) error {
+	a.health = health.New(a.config.Health)
+	return nil
+}
+
+// initHealthChecks проверяет только зависимости, которые созданы для серверов: подмененный
+// сервис или клиент не требует соединений с БД и other_service
+func (a *App) initHealthChecks(ctx context.Context) error {
+	if a.serviceProvider.resolvedDependency("db_client") {
+		dbClient, err := a.serviceProvider.DBClient(ctx)
+		if err != nil {
+			return err
+		}
+		a.health.Register("postgres", health.DB(dbClient.DB()))
+	}
+
+	if a.serviceProvider.resolvedDependency("other_service_conn") {
+		conn, err := a.serviceProvider.OtherServiceConn(ctx)
+		if err != nil {
+			return err
+		}
+		// Create не зависит от other_service, поэтому его недоступность не снимает готовность
+		a.health.RegisterOptional("other_service", health.GRPC(conn))
+	}
+
 	return nil
 }
 
 func (a *App) initGRPCServer(ctx context.Context) error {
-	tracing.Init(logger.Get(), "auth")
 	// a.grpcServer = grpc.NewServer(grpc.Creds(insecure.NewCredentials()))
 	logger.Info("init grpc server")
 
+	rateLimiter := interceptor.NewRateLimiter(a.config.RateLimit)
+	err := a.reloader.Subscribe("rate_limiter", rateLimiter.Reload)
+	if err != nil {
+		return err
+	}
+
+	creds, err := serverCredentials(a.serviceProvider.Certificates(), a.config.GRPC.TLS)
+	if err != nil {
+		return fmt.Errorf("failed to configure TLS for gRPC server: %w", err)
+	}
+
+	mon, err := a.serviceProvider.Monitoring()
+	if err != nil {
+		return err
+	}
+
 	a.grpcServer = grpc.NewServer(
-		grpc.Creds(insecure.NewCredentials()),
-		grpc.UnaryInterceptor(
-			grpcMiddleware.ChainUnaryServer(
-				interceptor.LogInterceptor,
-				interceptor.MetricsInterceptor,
-				interceptor.ServerTracingInterceptor,
-				otgrpc.OpenTracingServerInterceptor(opentracing.GlobalTracer()),
-			),
+		grpc.Creds(creds),
+		// Span вызова создается до интерцепторов, поэтому они видят его в контексте
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			interceptor.ServerTracingInterceptor,
+			interceptor.RequestIDInterceptor,
+			interceptor.RecoveryInterceptor,
+			// Внутри recovery: паника уже отправлена им, сюда приходят ошибки обработчиков
+			mon.GRPCUnaryInterceptor(),
+			interceptor.LogInterceptor,
+			interceptor.PeerIdentityInterceptor,
+			interceptor.MetricsInterceptor,
+			rateLimiter.Unary,
+			a.authorizer.Unary,
+		),
+		// Потоковая цепочка повторяет unary: тот же порядок и те же проверки
+		grpc.ChainStreamInterceptor(
+			interceptor.StreamServerTracingInterceptor,
+			interceptor.StreamRequestIDInterceptor,
+			interceptor.StreamRecoveryInterceptor,
+			mon.GRPCStreamInterceptor(),
+			interceptor.StreamLogInterceptor,
+			interceptor.StreamPeerIdentityInterceptor,
+			interceptor.StreamMetricsInterceptor,
+			rateLimiter.Stream,
+			a.authorizer.Stream,
 		),
 	)
 
 	reflection.Register(a.grpcServer)
 
-	desc.RegisterUserV1Server(a.grpcServer, a.serviceProvider.UserImpl(ctx))
+	userImpl, err := a.serviceProvider.UserImpl(ctx)
+	if err != nil {
+		return err
+	}
+
+	desc.RegisterUserV1Server(a.grpcServer, userImpl)
+	adminDesc.RegisterAdminV1Server(a.grpcServer, a.serviceProvider.AdminImpl())
+
+	// Регистрируется последним: статус выставляется для всех сервисов сервера
+	a.health.RegisterGRPC(a.grpcServer)
+
+	closer.Register(closer.PhaseServers, "grpc_server", a.stopGRPCServer)
 
 	return nil
 }
 
+// stopGRPCServer дожидается завершения текущих запросов, по истечении ctx обрывает их
+func (a *App) stopGRPCServer(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		a.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		a.grpcServer.Stop()
+		return fmt.Errorf("graceful stop interrupted: %w", ctx.Err())
+	}
+}
+
 func (a *App) runGRPCServer() error {
 	log.Printf("GRPC server is running on %s", a.serviceProvider.GRPCConfig().Address())
 
@@ -337,7 +538,7 @@ func (a *App) runGRPCServer() error {
 	}
 
 	err = a.grpcServer.Serve(list)
-	if err != nil {
+	if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
 		return err
 	}
 
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/rs/cors"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+)
+
+// corsHandler оборачивает HTTP шлюз в CORS middleware, настройки которого
+// можно менять при перезагрузке конфигурации
+type corsHandler struct {
+	next    http.Handler
+	handler atomic.Value
+}
+
+func newCORSHandler(next http.Handler, cfg config.CORS) *corsHandler {
+	h := &corsHandler{next: next}
+	h.handler.Store(newCORS(cfg).Handler(next))
+
+	return h
+}
+
+func (h *corsHandler) Reload(cfg config.Reloadable) error {
+	h.handler.Store(newCORS(cfg.CORS).Handler(h.next))
+	return nil
+}
+
+func (h *corsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.Load().(http.Handler).ServeHTTP(w, r)
+}
+
+func newCORS(cfg config.CORS) *cors.Cors {
+	return cors.New(cors.Options{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		ExposedHeaders:   cfg.ExposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge.Seconds()),
+	})
+}
@@ -0,0 +1,56 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/gateway"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/metric"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+)
+
+// recoveryHandler перехватывает панику HTTP обработчика так же, как RecoveryInterceptor для gRPC,
+// и отвечает 500 в формате ошибок шлюза с request ID.
+// http.ErrAbortHandler пробрасывается дальше: им обработчик сам прерывает ответ.
+func recoveryHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if err, ok := p.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(p)
+			}
+
+			stack := string(debug.Stack())
+			id, _ := requestid.FromContext(r.Context())
+
+			logger.NamedFromContext(r.Context(), logger.SubsystemHTTP).Error("Handler panicked",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Any("panic", p),
+				zap.String("stack", stack),
+				logger.Reported(),
+			)
+			// Событие уходит в scope запроса, созданный monitoring middleware, вместе с данными запроса
+			reporter.CaptureError(r.Context(), fmt.Errorf("panic in %s %s: %v", r.Method, r.URL.Path, p), reporter.Event{
+				Tags:  map[string]string{"path": r.URL.Path, "error_type": "panic"},
+				Extra: map[string]interface{}{"stack": stack},
+			})
+			metric.IncPanicRecovered("http")
+
+			gateway.WriteError(w, r, status.Error(codes.Internal, "internal error, request_id: "+id))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+)
+
+// retry вызывает f, пока она не вернет nil, не закончатся попытки или не отменится ctx.
+// Каждая попытка ограничена cfg.AttemptTimeout, задержка между попытками удваивается.
+func retry(ctx context.Context, cfg config.Startup, name string, f func(ctx context.Context) error) error {
+	backoff := cfg.Backoff
+
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, cfg.AttemptTimeout)
+		err := f(attemptCtx)
+		cancel()
+
+		if err == nil {
+			return nil
+		}
+		if attempt >= cfg.Attempts {
+			return fmt.Errorf("%d attempts failed: %w", attempt, err)
+		}
+
+		logger.Warn("Dependency is not available, retrying",
+			zap.String("dependency", name),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w, last error: %v", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, cfg.MaxBackoff)
+	}
+}
+
+// waitReady устанавливает соединение и ждет состояния READY
+func waitReady(ctx context.Context, conn *grpc.ClientConn) error {
+	conn.Connect()
+
+	for {
+		state := conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("connection is %s: %w", state, ctx.Err())
+		}
+	}
+}
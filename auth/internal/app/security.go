@@ -0,0 +1,38 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+)
+
+// securityHeaders добавляет заголовки безопасности ко всем ответам HTTP сервера.
+// Strict-Transport-Security отправляется только по HTTPS, в том числе за TLS прокси.
+// Обработчик может заменить Content-Security-Policy своей, как это делает Swagger UI.
+func securityHeaders(next http.Handler, cfg config.HTTPSecurity) http.Handler {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.FormatInt(int64(cfg.HSTSMaxAge.Seconds()), 10)
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Referrer-Policy", "no-referrer")
+		if cfg.FrameOptions != "" {
+			h.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		if cfg.ContentSecurityPolicy != "" {
+			h.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+		if hsts != "" && (r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https") {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
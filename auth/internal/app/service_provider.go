@@ -2,8 +2,16 @@ package app
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"slices"
+	"strings"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/MercerMorning/go_example/auth/internal/api/admin"
 	"github.com/MercerMorning/go_example/auth/internal/api/user"
 	"github.com/MercerMorning/go_example/auth/internal/client/db"
 	"github.com/MercerMorning/go_example/auth/internal/client/db/pg"
@@ -11,43 +19,113 @@ import (
 	"github.com/MercerMorning/go_example/auth/internal/closer"
 	"github.com/MercerMorning/go_example/auth/internal/config"
 	"github.com/MercerMorning/go_example/auth/internal/interceptor"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/monitoring"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
 	"github.com/MercerMorning/go_example/auth/internal/repository"
 	"github.com/MercerMorning/go_example/auth/internal/service"
+	"github.com/MercerMorning/go_example/auth/internal/tlsconfig"
 	desc "github.com/MercerMorning/go_example/auth/pkg/user_v1"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
 	userRepository "github.com/MercerMorning/go_example/auth/internal/repository/user"
 	userService "github.com/MercerMorning/go_example/auth/internal/service/user"
 )
 
+// Option подменяет зависимость в контейнере, например фейком в тестах.
+// Подмененная зависимость не создается, ее собственные зависимости не запрашиваются.
+type Option func(s *serviceProvider)
+
+// WithDBClient подменяет клиент БД
+func WithDBClient(client db.Client) Option {
+	return func(s *serviceProvider) {
+		s.dbClient = client
+		s.override("db_client")
+	}
+}
+
+// WithTxManager подменяет менеджер транзакций
+func WithTxManager(txManager db.TxManager) Option {
+	return func(s *serviceProvider) {
+		s.txManager = txManager
+		s.override("tx_manager")
+	}
+}
+
+// WithUserRepository подменяет репозиторий пользователей
+func WithUserRepository(repo repository.UserRepository) Option {
+	return func(s *serviceProvider) {
+		s.userRepository = repo
+		s.override("user_repository")
+	}
+}
+
+// WithUserService подменяет сервис пользователей
+func WithUserService(srv service.UserService) Option {
+	return func(s *serviceProvider) {
+		s.userService = srv
+		s.override("user_service")
+	}
+}
+
+// WithReporter подменяет backend отправки ошибок, например reporter.Recorder в тестах
+func WithReporter(r reporter.Reporter) Option {
+	return func(s *serviceProvider) {
+		s.reporter = r
+		s.override("error_reporter")
+	}
+}
+
+// WithUserClient подменяет клиент other_service
+func WithUserClient(client desc.UserV1Client) Option {
+	return func(s *serviceProvider) {
+		s.userClient = client
+		s.override("user_client")
+	}
+}
+
+// dependency узел графа зависимостей
+type dependency struct {
+	name     string
+	deps     []string
+	override bool
+}
+
 type serviceProvider struct {
+	config *config.Config
+	graph  []dependency
+
 	pgConfig   config.PGConfig
 	grpcConfig config.GRPCConfig
 	httpConfig config.HTTPConfig
+	certs      *tlsconfig.Store
+	reporter   reporter.Reporter
+	monitoring *monitoring.Middleware
 
 	dbClient       db.Client
 	txManager      db.TxManager
 	userRepository repository.UserRepository
 
-	userService service.UserService
-	userClient  desc.UserV1Client
+	userService      service.UserService
+	otherServiceConn *grpc.ClientConn
+	userClient       desc.UserV1Client
 
-	userImpl *user.Implementation
+	userImpl  *user.Implementation
+	adminImpl *admin.Implementation
 }
 
-func newServiceProvider() *serviceProvider {
-	return &serviceProvider{}
+func newServiceProvider(cfg *config.Config, opts ...Option) *serviceProvider {
+	s := &serviceProvider{config: cfg}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 func (s *serviceProvider) HTTPConfig() config.HTTPConfig {
 	if s.httpConfig == nil {
-		cfg, err := config.NewHTTPConfig()
-		if err != nil {
-			log.Fatalf("failed to get http config: %s", err.Error())
-		}
-
-		s.httpConfig = cfg
+		s.httpConfig = s.config.HTTPConfig()
+		s.resolved("http_config")
 	}
 
 	return s.httpConfig
@@ -55,12 +133,8 @@ func (s *serviceProvider) HTTPConfig() config.HTTPConfig {
 
 func (s *serviceProvider) PGConfig() config.PGConfig {
 	if s.pgConfig == nil {
-		cfg, err := config.NewPGConfig()
-		if err != nil {
-			log.Fatalf("failed to get pg config: %s", err.Error())
-		}
-
-		s.pgConfig = cfg
+		s.pgConfig = s.config.PGConfig()
+		s.resolved("pg_config")
 	}
 
 	return s.pgConfig
@@ -68,85 +142,264 @@ func (s *serviceProvider) PGConfig() config.PGConfig {
 
 func (s *serviceProvider) GRPCConfig() config.GRPCConfig {
 	if s.grpcConfig == nil {
-		cfg, err := config.NewGRPCConfig()
+		s.grpcConfig = s.config.GRPCConfig()
+		s.resolved("grpc_config")
+	}
+
+	return s.grpcConfig
+}
+
+// Certificates возвращает хранилище TLS сертификатов серверов и клиентов
+func (s *serviceProvider) Certificates() *tlsconfig.Store {
+	if s.certs == nil {
+		s.certs = tlsconfig.NewStore(s.config.Reload.CertInterval)
+		s.resolved("certificates")
+	}
+
+	return s.certs
+}
+
+// Reporter создает backend отправки ошибок по секции error_reporting (monitoring.NewReporter)
+func (s *serviceProvider) Reporter() (reporter.Reporter, error) {
+	if s.reporter == nil {
+		r, closeReporter, err := monitoring.NewReporter(s.config.Reporting, s.config.Sentry)
+		if err != nil {
+			return nil, err
+		}
+		closer.Register(closer.PhaseTelemetry, "error_reporter", func(ctx context.Context) error {
+			// Ошибки лога из очереди SentryCore отправляются до закрытия Reporter.
+			// Ошибку Sync не проверяем: stdout терминала и pipe не поддерживают Sync.
+			_ = logger.Sync()
+			return closeReporter(ctx)
+		})
+
+		s.reporter = r
+		s.resolved("error_reporter")
+	}
+
+	return s.reporter, nil
+}
+
+// Monitoring возвращает middleware серверов и хук БД, которые отправляют ошибки
+// и медленные операции в Reporter
+func (s *serviceProvider) Monitoring() (*monitoring.Middleware, error) {
+	if s.monitoring == nil {
+		r, err := s.Reporter()
 		if err != nil {
-			log.Fatalf("failed to get grpc config: %s", err.Error())
+			return nil, err
 		}
 
-		s.grpcConfig = cfg
+		s.monitoring = monitoring.NewMiddleware(r, monitoring.LoadThresholds(s.config.Sentry.SlowThreshold))
+		s.resolved("monitoring", "error_reporter")
 	}
 
-	return s.grpcConfig
+	return s.monitoring, nil
 }
 
-func (s *serviceProvider) DBClient(ctx context.Context) db.Client {
+func (s *serviceProvider) DBClient(ctx context.Context) (db.Client, error) {
 	if s.dbClient == nil {
-		cl, err := pg.New(ctx, s.PGConfig().DSN())
+		dsn := s.PGConfig().DSN()
+
+		mon, err := s.Monitoring()
 		if err != nil {
-			log.Fatalf("failed to create db client: %v", err)
+			return nil, err
 		}
 
-		err = cl.DB().Ping(ctx)
+		var cl db.Client
+		err = retry(ctx, s.config.Startup, "postgres", func(ctx context.Context) error {
+			c, err := pg.New(ctx, dsn, pg.WithQueryHook(mon.DatabaseMiddleware()))
+			if err != nil {
+				return err
+			}
+
+			err = c.DB().Ping(ctx)
+			if err != nil {
+				_ = c.Close()
+				return fmt.Errorf("ping error: %w", err)
+			}
+
+			cl = c
+			return nil
+		})
 		if err != nil {
-			log.Fatalf("ping error: %s", err.Error())
+			return nil, fmt.Errorf("failed to create db client: %w", err)
 		}
-		closer.Add(cl.Close)
+
+		closer.Register(closer.PhaseDB, "postgres", func(context.Context) error {
+			return cl.Close()
+		})
 
 		s.dbClient = cl
+		s.resolved("db_client", "pg_config", "monitoring")
 	}
 
-	return s.dbClient
+	return s.dbClient, nil
 }
 
-func (s *serviceProvider) TxManager(ctx context.Context) db.TxManager {
+func (s *serviceProvider) TxManager(ctx context.Context) (db.TxManager, error) {
 	if s.txManager == nil {
-		s.txManager = transaction.NewTransactionManager(s.DBClient(ctx).DB())
+		cl, err := s.DBClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		s.txManager = transaction.NewTransactionManager(cl.DB())
+		s.resolved("tx_manager", "db_client")
 	}
 
-	return s.txManager
+	return s.txManager, nil
 }
 
-func (s *serviceProvider) NoteRepository(ctx context.Context) repository.UserRepository {
+func (s *serviceProvider) UserRepository(ctx context.Context) (repository.UserRepository, error) {
 	if s.userRepository == nil {
-		s.userRepository = userRepository.NewRepository(s.DBClient(ctx))
+		cl, err := s.DBClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		s.userRepository = userRepository.NewRepository(cl)
+		s.resolved("user_repository", "db_client")
 	}
 
-	return s.userRepository
+	return s.userRepository, nil
 }
 
-func (s *serviceProvider) UserService(ctx context.Context) service.UserService {
+func (s *serviceProvider) UserService(ctx context.Context) (service.UserService, error) {
 	if s.userService == nil {
-		s.userService = userService.NewService(
-			s.NoteRepository(ctx),
-			s.TxManager(ctx),
+		repo, err := s.UserRepository(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		txManager, err := s.TxManager(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		s.userService = userService.NewService(repo, txManager)
+		s.resolved("user_service", "user_repository", "tx_manager")
+	}
+
+	return s.userService, nil
+}
+
+// OtherServiceConn подключается к other_service. Недоступность other_service при старте
+// не считается ошибкой: Create работает и без него, а соединение восстановится само.
+func (s *serviceProvider) OtherServiceConn(ctx context.Context) (*grpc.ClientConn, error) {
+	if s.otherServiceConn == nil {
+		creds, err := clientCredentials(s.Certificates(), s.config.OtherService.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure TLS for other_service: %w", err)
+		}
+
+		// Контекст трейса передается в other_service в заголовке traceparent
+		conn, err := grpc.Dial(s.config.OtherService.Address,
+			grpc.WithTransportCredentials(creds),
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler(
+				otelgrpc.WithSpanAttributes(semconv.PeerService("other_service")),
+			)),
+			grpc.WithChainUnaryInterceptor(
+				interceptor.ClientRequestIDInterceptor,
+			),
 		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to other_service: %w", err)
+		}
+		closer.Register(closer.PhaseClients, "other_service", func(context.Context) error {
+			return conn.Close()
+		})
+
+		err = retry(ctx, s.config.Startup, "other_service", func(ctx context.Context) error {
+			return waitReady(ctx, conn)
+		})
+		if err != nil {
+			logger.Warn("other_service is not available, starting without it", zap.Error(err))
+		}
+
+		s.otherServiceConn = conn
+		s.resolved("other_service_conn", "certificates")
 	}
 
-	return s.userService
+	return s.otherServiceConn, nil
 }
 
-func (s *serviceProvider) UserClient(ctx context.Context) desc.UserV1Client {
+func (s *serviceProvider) UserClient(ctx context.Context) (desc.UserV1Client, error) {
 	if s.userClient == nil {
-		// Подключаемся к other_service на порту 50052 с клиентским интерцептором для трейсинга
-		conn, err := grpc.Dial("localhost:50052", 
-			grpc.WithTransportCredentials(insecure.NewCredentials()),
-			grpc.WithUnaryInterceptor(interceptor.ClientTracingInterceptor),
-		)
+		conn, err := s.OtherServiceConn(ctx)
 		if err != nil {
-			log.Fatalf("failed to connect to other_service: %v", err)
+			return nil, err
 		}
 
 		s.userClient = desc.NewUserV1Client(conn)
-		closer.Add(conn.Close)
+		s.resolved("user_client", "other_service_conn")
 	}
 
-	return s.userClient
+	return s.userClient, nil
 }
 
-func (s *serviceProvider) UserImpl(ctx context.Context) *user.Implementation {
+func (s *serviceProvider) UserImpl(ctx context.Context) (*user.Implementation, error) {
 	if s.userImpl == nil {
-		s.userImpl = user.NewImplementation(s.UserService(ctx), s.UserClient(ctx))
+		userSrv, err := s.UserService(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		userClient, err := s.UserClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		s.userImpl = user.NewImplementation(userSrv, userClient)
+		s.resolved("user_impl", "user_service", "user_client")
+	}
+
+	return s.userImpl, nil
+}
+
+func (s *serviceProvider) AdminImpl() *admin.Implementation {
+	if s.adminImpl == nil {
+		s.adminImpl = admin.NewImplementation()
+		s.resolved("admin_impl")
+	}
+
+	return s.adminImpl
+}
+
+// DependencyGraph возвращает созданные зависимости в порядке создания:
+// "user_service -> user_repository, tx_manager", подмененные помечены [override]
+func (s *serviceProvider) DependencyGraph() string {
+	var b strings.Builder
+	for _, d := range s.graph {
+		b.WriteString(d.name)
+		if d.override {
+			b.WriteString(" [override]")
+		}
+		if len(d.deps) > 0 {
+			b.WriteString(" -> ")
+			b.WriteString(strings.Join(d.deps, ", "))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func (s *serviceProvider) resolved(name string, deps ...string) {
+	s.graph = append(s.graph, dependency{name: name, deps: deps})
+}
+
+func (s *serviceProvider) override(name string) {
+	s.graph = append(s.graph, dependency{name: name, override: true})
+}
+
+// resolvedDependency сообщает, что зависимость используется: создана контейнером
+// или подменена опцией и нужна другой созданной зависимости
+func (s *serviceProvider) resolvedDependency(name string) bool {
+	for _, d := range s.graph {
+		if (d.name == name && !d.override) || slices.Contains(d.deps, name) {
+			return true
+		}
 	}
 
-	return s.userImpl
+	return false
 }
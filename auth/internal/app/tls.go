@@ -0,0 +1,27 @@
+package app
+
+import (
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/tlsconfig"
+)
+
+func serverCredentials(certs *tlsconfig.Store, cfg config.ServerTLS) (credentials.TransportCredentials, error) {
+	tlsConfig, err := certs.Server(cfg)
+	if err != nil || tlsConfig == nil {
+		return insecure.NewCredentials(), err
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func clientCredentials(certs *tlsconfig.Store, cfg config.ClientTLS) (credentials.TransportCredentials, error) {
+	tlsConfig, err := certs.Client(cfg)
+	if err != nil || tlsConfig == nil {
+		return insecure.NewCredentials(), err
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
@@ -28,6 +29,10 @@ type Query struct {
 	QueryRaw string
 }
 
+// QueryHook вызывается после каждого запроса, например для отправки ошибок и медленных
+// запросов в мониторинг. err = nil для успешного запроса и для pgx.ErrNoRows.
+type QueryHook func(ctx context.Context, q Query, duration time.Duration, err error)
+
 // Transactor интерфейс для работы с транзакциями
 type Transactor interface {
 	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
@@ -0,0 +1,56 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var migrationNameRegex = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Create создает пару файлов up/down для новой миграции с версией из текущего времени
+func Create(migrationsPath, name string, now time.Time) (upFile, downFile string, err error) {
+	name = strings.ToLower(strings.Join(strings.Fields(name), "_"))
+	if !migrationNameRegex.MatchString(name) {
+		return "", "", fmt.Errorf("invalid migration name %q: use latin letters, digits and underscores", name)
+	}
+
+	if err = os.MkdirAll(migrationsPath, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create migrations directory: %w", err)
+	}
+
+	base := fmt.Sprintf("%s_%s", now.UTC().Format(versionTimeLayout), name)
+	upFile = filepath.Join(migrationsPath, base+".up.sql")
+	downFile = filepath.Join(migrationsPath, base+".down.sql")
+
+	if err = writeNewFile(upFile, "-- +migrate Up\n-- Add your migration here\n"); err != nil {
+		return "", "", err
+	}
+	if err = writeNewFile(downFile, "-- +migrate Down\n-- Add your rollback here\n"); err != nil {
+		_ = os.Remove(upFile)
+		return "", "", err
+	}
+
+	return upFile, downFile, nil
+}
+
+func writeNewFile(path, content string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("migration file %s already exists", path)
+		}
+		return fmt.Errorf("failed to create migration file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err = f.WriteString(content); err != nil {
+		return fmt.Errorf("failed to write migration file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,351 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Правила линтера миграций
+const (
+	RuleIndexNotConcurrent    = "index-not-concurrent"
+	RuleNotNullWithoutDefault = "not-null-without-default"
+	RuleSetNotNull            = "set-not-null"
+	RuleConstraintNotValid    = "constraint-not-valid"
+	RuleTableRewrite          = "table-rewrite"
+	RuleMissingDown           = "missing-down"
+)
+
+// allowDirective комментарий, отключающий правило для следующего за ним запроса:
+//
+//	-- migrate-lint:allow index-not-concurrent,set-not-null
+const allowDirective = "migrate-lint:allow"
+
+// Issue найденная линтером проблема в миграции
+type Issue struct {
+	File    string
+	Line    int
+	Rule    string
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d: [%s] %s", i.File, i.Line, i.Rule, i.Message)
+}
+
+var (
+	createTableRegex = regexp.MustCompile(`^CREATE (?:UNLOGGED |TEMP |TEMPORARY )?TABLE (?:IF NOT EXISTS )?([\w."]+)`)
+	createIndexRegex = regexp.MustCompile(`^CREATE (?:UNIQUE )?INDEX (CONCURRENTLY )?.*?\bON (?:ONLY )?([\w."]+)`)
+	alterTableRegex  = regexp.MustCompile(`^ALTER TABLE (?:IF EXISTS )?(?:ONLY )?([\w."]+) (.*)$`)
+	rewriteRegex     = regexp.MustCompile(`^(VACUUM (?:\(.*FULL.*\)|FULL)|CLUSTER)\b`)
+
+	addColumnRegex      = regexp.MustCompile(`^ADD (?:COLUMN )?(?:IF NOT EXISTS )?`)
+	addConstraintRegex  = regexp.MustCompile(`^ADD (?:CONSTRAINT [\w."]+ )?(FOREIGN KEY|CHECK)\b`)
+	alterColumnType     = regexp.MustCompile(`^ALTER (?:COLUMN )?[\w."]+ (?:SET DATA )?TYPE\b`)
+	alterColumnNotNull  = regexp.MustCompile(`^ALTER (?:COLUMN )?[\w."]+ SET NOT NULL\b`)
+	tableLevelAddRegex  = regexp.MustCompile(`^ADD (?:CONSTRAINT|PRIMARY KEY|UNIQUE|FOREIGN KEY|CHECK|EXCLUDE)\b`)
+	setTablespaceRegex  = regexp.MustCompile(`^SET TABLESPACE\b`)
+	whitespaceRegex     = regexp.MustCompile(`\s+`)
+	allowDirectiveRegex = regexp.MustCompile(regexp.QuoteMeta(allowDirective) + `\s+([\w\-, ]+)`)
+)
+
+// Lint проверяет все *.sql файлы миграций на операции, которые блокируют таблицы
+// надолго или не могут быть откачены
+func Lint(migrationsPath string) ([]Issue, error) {
+	files, err := filepath.Glob(filepath.Join(migrationsPath, "*.sql"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	sort.Strings(files)
+
+	existing := make(map[string]bool, len(files))
+	for _, f := range files {
+		existing[filepath.Base(f)] = true
+	}
+
+	var issues []Issue
+	for _, f := range files {
+		name := filepath.Base(f)
+
+		if strings.HasSuffix(name, ".up.sql") {
+			down := strings.TrimSuffix(name, ".up.sql") + ".down.sql"
+			if !existing[down] {
+				issues = append(issues, Issue{
+					File:    name,
+					Line:    1,
+					Rule:    RuleMissingDown,
+					Message: fmt.Sprintf("migration has no %s, it cannot be rolled back", down),
+				})
+			}
+		}
+
+		body, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+
+		issues = append(issues, LintSQL(name, string(body))...)
+	}
+
+	return issues, nil
+}
+
+// LintSQL проверяет SQL одного файла миграции
+func LintSQL(file, sql string) []Issue {
+	statements := splitStatements(sql)
+
+	// Таблицы, созданные в этом же файле, пусты, поэтому блокировки на них безопасны
+	created := make(map[string]bool)
+	for _, st := range statements {
+		if m := createTableRegex.FindStringSubmatch(st.text); m != nil {
+			created[normalizeIdent(m[1])] = true
+		}
+	}
+
+	var issues []Issue
+	for _, st := range statements {
+		for _, issue := range lintStatement(st, created) {
+			if st.allowed[issue.Rule] {
+				continue
+			}
+			issue.File = file
+			issue.Line = st.line
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+func lintStatement(st statement, created map[string]bool) []Issue {
+	var issues []Issue
+
+	if m := createIndexRegex.FindStringSubmatch(st.text); m != nil {
+		if m[1] == "" && !created[normalizeIdent(m[2])] {
+			issues = append(issues, Issue{
+				Rule:    RuleIndexNotConcurrent,
+				Message: fmt.Sprintf("CREATE INDEX on existing table %s without CONCURRENTLY blocks writes for the whole build", m[2]),
+			})
+		}
+		return issues
+	}
+
+	if m := rewriteRegex.FindStringSubmatch(st.text); m != nil {
+		return append(issues, Issue{
+			Rule:    RuleTableRewrite,
+			Message: fmt.Sprintf("%s rewrites the table under ACCESS EXCLUSIVE lock", m[1]),
+		})
+	}
+
+	m := alterTableRegex.FindStringSubmatch(st.text)
+	if m == nil || created[normalizeIdent(m[1])] {
+		return issues
+	}
+	table := m[1]
+
+	for _, action := range splitTopLevel(m[2], ',') {
+		switch {
+		case addConstraintRegex.MatchString(action):
+			if !strings.Contains(action, "NOT VALID") {
+				issues = append(issues, Issue{
+					Rule:    RuleConstraintNotValid,
+					Message: fmt.Sprintf("adding a constraint to %s validates all rows under lock, add it NOT VALID and VALIDATE separately", table),
+				})
+			}
+		case tableLevelAddRegex.MatchString(action):
+		case addColumnRegex.MatchString(action):
+			if strings.Contains(action, "NOT NULL") && !strings.Contains(action, "DEFAULT") {
+				issues = append(issues, Issue{
+					Rule:    RuleNotNullWithoutDefault,
+					Message: fmt.Sprintf("adding NOT NULL column without DEFAULT to %s fails on non-empty tables", table),
+				})
+			}
+		case alterColumnType.MatchString(action):
+			issues = append(issues, Issue{
+				Rule:    RuleTableRewrite,
+				Message: fmt.Sprintf("changing column type rewrites %s under ACCESS EXCLUSIVE lock", table),
+			})
+		case alterColumnNotNull.MatchString(action):
+			issues = append(issues, Issue{
+				Rule:    RuleSetNotNull,
+				Message: fmt.Sprintf("SET NOT NULL scans %s under ACCESS EXCLUSIVE lock, add a NOT VALID CHECK constraint first", table),
+			})
+		case setTablespaceRegex.MatchString(action):
+			issues = append(issues, Issue{
+				Rule:    RuleTableRewrite,
+				Message: fmt.Sprintf("SET TABLESPACE rewrites %s under ACCESS EXCLUSIVE lock", table),
+			})
+		}
+	}
+
+	return issues
+}
+
+// statement SQL-запрос без комментариев в верхнем регистре с нормализованными пробелами
+type statement struct {
+	text    string
+	line    int
+	allowed map[string]bool
+}
+
+// splitStatements разбивает SQL на запросы по ';' с учетом строк, комментариев и $$-блоков.
+// Комментарии перед запросом и внутри него просматриваются на директиву allowDirective.
+func splitStatements(sql string) []statement {
+	var (
+		statements []statement
+		text       strings.Builder
+		comments   []string
+		line       = 1
+		startLine  = 0
+	)
+
+	flush := func() {
+		t := strings.TrimSpace(whitespaceRegex.ReplaceAllString(text.String(), " "))
+		if t != "" {
+			statements = append(statements, statement{
+				text:    strings.ToUpper(t),
+				line:    startLine,
+				allowed: parseAllowed(comments),
+			})
+		}
+		text.Reset()
+		comments = nil
+		startLine = 0
+	}
+
+	write := func(s string) {
+		if startLine == 0 && strings.TrimSpace(s) != "" {
+			startLine = line
+		}
+		text.WriteString(s)
+		line += strings.Count(s, "\n")
+	}
+
+	for i := 0; i < len(sql); {
+		rest := sql[i:]
+		switch {
+		case strings.HasPrefix(rest, "--"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			comments = append(comments, rest[:end])
+			i += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest, "*/")
+			if end < 0 {
+				end = len(rest)
+			} else {
+				end += 2
+			}
+			comments = append(comments, rest[:end])
+			line += strings.Count(rest[:end], "\n")
+			text.WriteString(" ")
+			i += end
+		case rest[0] == '\'':
+			end := 1
+			for end < len(rest) {
+				if rest[end] == '\'' {
+					if end+1 < len(rest) && rest[end+1] == '\'' {
+						end += 2
+						continue
+					}
+					end++
+					break
+				}
+				end++
+			}
+			write(rest[:end])
+			i += end
+		case rest[0] == '$':
+			tag := dollarTag(rest)
+			if tag == "" {
+				write(rest[:1])
+				i++
+				continue
+			}
+			end := strings.Index(rest[len(tag):], tag)
+			if end < 0 {
+				end = len(rest)
+			} else {
+				end += 2 * len(tag)
+			}
+			write(rest[:end])
+			i += end
+		case rest[0] == ';':
+			flush()
+			i++
+		default:
+			write(rest[:1])
+			i++
+		}
+	}
+	flush()
+
+	return statements
+}
+
+// dollarTag возвращает открывающий тег $tag$ в начале s или пустую строку
+func dollarTag(s string) string {
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if c == '$' {
+			return s[:i+1]
+		}
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+// splitTopLevel разбивает s по sep вне скобок
+func splitTopLevel(s string, sep byte) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+func parseAllowed(comments []string) map[string]bool {
+	allowed := make(map[string]bool)
+	for _, c := range comments {
+		m := allowDirectiveRegex.FindStringSubmatch(c)
+		if m == nil {
+			continue
+		}
+		for _, rule := range strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ' ' }) {
+			allowed[strings.ToLower(rule)] = true
+		}
+	}
+
+	return allowed
+}
+
+func normalizeIdent(name string) string {
+	name = strings.ToLower(strings.ReplaceAll(name, `"`, ""))
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
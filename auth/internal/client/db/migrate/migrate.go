@@ -2,41 +2,94 @@ package migrate
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"path/filepath"
 
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/lib/pq"
 )
 
 type Migrator struct {
 	migrate *migrate.Migrate
+	source  source.Driver
+	db      *sql.DB
+	path    string
 }
 
 func NewMigrator(dsn string, migrationsPath string) (*Migrator, error) {
+	absPath, src, err := openSource(migrationsPath)
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
+		_ = src.Close()
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
+		_ = src.Close()
+		_ = db.Close()
 		return nil, fmt.Errorf("failed to create postgres driver: %w", err)
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
-		"postgres",
-		driver,
-	)
+	// Драйвер закрывает и db
+	m, err := newMigrator(absPath, src, "postgres", driver)
+	if err != nil {
+		return nil, err
+	}
+	m.db = db
+
+	return m, nil
+}
+
+// NewMigratorWithDriver создает Migrator поверх открытого драйвера БД, например
+// database/stub в тестах. Verify с таким Migrator недоступен: ему нужен *sql.DB.
+func NewMigratorWithDriver(migrationsPath string, driver database.Driver) (*Migrator, error) {
+	absPath, src, err := openSource(migrationsPath)
+	if err != nil {
+		_ = driver.Close()
+		return nil, err
+	}
+
+	return newMigrator(absPath, src, "driver", driver)
+}
+
+func openSource(migrationsPath string) (string, source.Driver, error) {
+	absPath, err := filepath.Abs(migrationsPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve migrations path: %w", err)
+	}
+
+	src, err := source.Open(fmt.Sprintf("file://%s", absPath))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to open migrations source: %w", err)
+	}
+
+	return absPath, src, nil
+}
+
+// newMigrator забирает src и driver: при ошибке они закрываются
+func newMigrator(absPath string, src source.Driver, driverName string, driver database.Driver) (*Migrator, error) {
+	m, err := migrate.NewWithInstance("file", src, driverName, driver)
 	if err != nil {
+		_ = src.Close()
+		_ = driver.Close()
 		return nil, fmt.Errorf("failed to create migrator: %w", err)
 	}
 
 	return &Migrator{
 		migrate: m,
+		source:  src,
+		path:    absPath,
 	}, nil
 }
 
@@ -56,6 +109,24 @@ func (m *Migrator) Down() error {
 	return nil
 }
 
+// Steps применяет n миграций вверх (n > 0) или откатывает -n миграций (n < 0)
+func (m *Migrator) Steps(n int) error {
+	if err := m.migrate.Steps(n); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("failed to run %d migration steps: %w", n, err)
+	}
+	log.Printf("Migration steps applied successfully: %d", n)
+	return nil
+}
+
+// Goto приводит схему к указанной версии, применяя или откатывая миграции
+func (m *Migrator) Goto(version uint) error {
+	if err := m.migrate.Migrate(version); err != nil && err != migrate.ErrNoChange {
+		return fmt.Errorf("failed to migrate to version %d: %w", version, err)
+	}
+	log.Printf("Migrated to version %d", version)
+	return nil
+}
+
 func (m *Migrator) Force(version int) error {
 	if err := m.migrate.Force(version); err != nil {
 		return fmt.Errorf("failed to force migration version: %w", err)
@@ -72,6 +143,18 @@ func (m *Migrator) Version() (uint, bool, error) {
 	return version, dirty, nil
 }
 
+// currentVersion возвращает текущую версию схемы; ok == false, если ни одна миграция не применена
+func (m *Migrator) currentVersion() (version uint, dirty bool, ok bool, err error) {
+	version, dirty, err = m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, false, nil
+	}
+	if err != nil {
+		return 0, false, false, fmt.Errorf("failed to get migration version: %w", err)
+	}
+	return version, dirty, true, nil
+}
+
 func (m *Migrator) Close() error {
 	sourceErr, dbErr := m.migrate.Close()
 	if sourceErr != nil {
@@ -0,0 +1,221 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+)
+
+const versionTimeLayout = "20060102150405"
+
+// Direction направление применения миграции
+type Direction string
+
+const (
+	DirectionUp   Direction = "up"
+	DirectionDown Direction = "down"
+)
+
+// MigrationStatus состояние одной миграции из директории migrations
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	CreatedAt time.Time
+	Applied   bool
+}
+
+// PlannedMigration миграция, которая будет выполнена командой, вместе с её SQL
+type PlannedMigration struct {
+	Version   uint
+	Name      string
+	Direction Direction
+	SQL       string
+}
+
+// Status возвращает список всех миграций с отметкой, применена ли миграция к БД.
+// Время создания берется из версии миграции (YYYYMMDDHHMMSS), т.к. golang-migrate
+// не хранит время применения.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	current, _, ok, err := m.currentVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := m.versions()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(versions))
+	for _, v := range versions {
+		_, name, err := m.readMigration(v, DirectionUp)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, MigrationStatus{
+			Version:   v,
+			Name:      name,
+			CreatedAt: versionTime(v),
+			Applied:   ok && v <= current,
+		})
+	}
+
+	return statuses, nil
+}
+
+// PlanUp возвращает миграции, которые применит Up
+func (m *Migrator) PlanUp() ([]PlannedMigration, error) {
+	return m.plan(func(_ int, versions []uint) (int, error) {
+		return len(versions) - 1, nil
+	})
+}
+
+// PlanDown возвращает миграции, которые откатит Down
+func (m *Migrator) PlanDown() ([]PlannedMigration, error) {
+	return m.plan(func(_ int, _ []uint) (int, error) {
+		return -1, nil
+	})
+}
+
+// PlanSteps возвращает миграции, которые выполнит Steps(n)
+func (m *Migrator) PlanSteps(n int) ([]PlannedMigration, error) {
+	return m.plan(func(current int, versions []uint) (int, error) {
+		target := current + n
+		if target < -1 {
+			target = -1
+		}
+		if target > len(versions)-1 {
+			target = len(versions) - 1
+		}
+		return target, nil
+	})
+}
+
+// PlanGoto возвращает миграции, которые выполнит Goto(version)
+func (m *Migrator) PlanGoto(version uint) ([]PlannedMigration, error) {
+	return m.plan(func(_ int, versions []uint) (int, error) {
+		idx := indexOf(versions, version)
+		if idx < 0 {
+			return 0, fmt.Errorf("migration version %d not found", version)
+		}
+		return idx, nil
+	})
+}
+
+// plan строит список миграций между текущей версией и целевой позицией,
+// которую вычисляет target по индексу текущей версии (-1 — ничего не применено)
+func (m *Migrator) plan(target func(current int, versions []uint) (int, error)) ([]PlannedMigration, error) {
+	current, dirty, ok, err := m.currentVersion()
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("database version %d is dirty, fix and force version first", current)
+	}
+
+	versions, err := m.versions()
+	if err != nil {
+		return nil, err
+	}
+
+	currentIdx := -1
+	if ok {
+		currentIdx = indexOf(versions, current)
+		if currentIdx < 0 {
+			return nil, fmt.Errorf("applied version %d not found in migrations", current)
+		}
+	}
+
+	targetIdx, err := target(currentIdx, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	var planned []PlannedMigration
+	for i := currentIdx + 1; i <= targetIdx; i++ {
+		p, err := m.planned(versions[i], DirectionUp)
+		if err != nil {
+			return nil, err
+		}
+		planned = append(planned, p)
+	}
+	for i := currentIdx; i > targetIdx; i-- {
+		p, err := m.planned(versions[i], DirectionDown)
+		if err != nil {
+			return nil, err
+		}
+		planned = append(planned, p)
+	}
+
+	return planned, nil
+}
+
+func (m *Migrator) planned(version uint, direction Direction) (PlannedMigration, error) {
+	sql, name, err := m.readMigration(version, direction)
+	if err != nil {
+		return PlannedMigration{}, err
+	}
+
+	return PlannedMigration{
+		Version:   version,
+		Name:      name,
+		Direction: direction,
+		SQL:       sql,
+	}, nil
+}
+
+// versions возвращает все версии миграций из источника по возрастанию
+func (m *Migrator) versions() ([]uint, error) {
+	var versions []uint
+
+	v, err := m.source.First()
+	for err == nil {
+		versions = append(versions, v)
+		v, err = m.source.Next(v)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	return versions, nil
+}
+
+func (m *Migrator) readMigration(version uint, direction Direction) (string, string, error) {
+	read := m.source.ReadUp
+	if direction == DirectionDown {
+		read = m.source.ReadDown
+	}
+
+	r, name, err := read(version)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read %s migration %d: %w", direction, version, err)
+	}
+	defer r.Close()
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read %s migration %d: %w", direction, version, err)
+	}
+
+	return string(body), name, nil
+}
+
+func versionTime(version uint) time.Time {
+	t, err := time.Parse(versionTimeLayout, fmt.Sprintf("%d", version))
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func indexOf(versions []uint, version uint) int {
+	for i, v := range versions {
+		if v == version {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MercerMorning/go_example/auth/internal/client/db/migrate"
+)
+
+func TestLintSQL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		sql   string
+		rules []string
+	}{
+		{
+			name: "new table with indexes",
+			sql: `CREATE TABLE IF NOT EXISTS users (id BIGSERIAL PRIMARY KEY, email TEXT NOT NULL);
+				CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);`,
+		},
+		{
+			name:  "index on existing table",
+			sql:   `CREATE INDEX idx_users_role ON users (role);`,
+			rules: []string{migrate.RuleIndexNotConcurrent},
+		},
+		{
+			name: "concurrent index",
+			sql:  `CREATE INDEX CONCURRENTLY idx_users_role ON users (role);`,
+		},
+		{
+			name:  "not null column without default",
+			sql:   `ALTER TABLE users ADD COLUMN phone VARCHAR(20) NOT NULL;`,
+			rules: []string{migrate.RuleNotNullWithoutDefault},
+		},
+		{
+			name: "not null column with default",
+			sql:  `ALTER TABLE users ADD COLUMN phone VARCHAR(20) NOT NULL DEFAULT '';`,
+		},
+		{
+			name: "several actions",
+			sql: `ALTER TABLE users
+				ALTER COLUMN name TYPE TEXT,
+				ALTER COLUMN role SET NOT NULL,
+				ADD CONSTRAINT users_role_check CHECK (role IN ('USER', 'ADMIN'));`,
+			rules: []string{migrate.RuleTableRewrite, migrate.RuleSetNotNull, migrate.RuleConstraintNotValid},
+		},
+		{
+			name: "not valid constraint",
+			sql:  `ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('USER', 'ADMIN')) NOT VALID;`,
+		},
+		{
+			name: "allow comment",
+			sql: `-- migrate-lint:allow index-not-concurrent
+				CREATE INDEX idx_users_role ON users (role);
+				CREATE INDEX idx_users_name ON users (name);`,
+			rules: []string{migrate.RuleIndexNotConcurrent},
+		},
+		{
+			name: "keywords inside strings and functions",
+			sql: `COMMENT ON TABLE users IS 'CREATE INDEX x ON users(id); ALTER TABLE users ALTER COLUMN id TYPE TEXT';
+				CREATE FUNCTION f() RETURNS void AS $$ BEGIN ALTER TABLE users ALTER COLUMN id TYPE TEXT; END $$ LANGUAGE plpgsql;`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			issues := migrate.LintSQL("test.up.sql", tt.sql)
+
+			rules := make([]string, 0, len(issues))
+			for _, issue := range issues {
+				rules = append(rules, issue.Rule)
+			}
+			require.ElementsMatch(t, tt.rules, rules)
+		})
+	}
+}
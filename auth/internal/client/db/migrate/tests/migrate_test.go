@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/stub"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MercerMorning/go_example/auth/internal/client/db/migrate"
+)
+
+const (
+	createUsers = uint(20240101100000)
+	addRole     = uint(20240102110000)
+	addIndex    = uint(20240103120000)
+)
+
+// newMigrator создает Migrator над тремя миграциями и БД-заглушкой с версией current
+// (database.NilVersion — ничего не применено)
+func newMigrator(t *testing.T, current int, dirty bool) *migrate.Migrator {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, name := range []string{"20240101100000_create_users", "20240102110000_add_role", "20240103120000_add_index"} {
+		writeFile(t, filepath.Join(dir, name+".up.sql"), "-- up "+name)
+		writeFile(t, filepath.Join(dir, name+".down.sql"), "-- down "+name)
+	}
+
+	driver, err := stub.WithInstance(nil, &stub.Config{})
+	require.NoError(t, err)
+	require.NoError(t, driver.SetVersion(current, dirty))
+
+	m, err := migrate.NewMigratorWithDriver(dir, driver)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	return m
+}
+
+type step struct {
+	version   uint
+	direction migrate.Direction
+}
+
+func steps(planned []migrate.PlannedMigration) []step {
+	out := make([]step, 0, len(planned))
+	for _, p := range planned {
+		out = append(out, step{p.Version, p.Direction})
+	}
+	return out
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	statuses, err := newMigrator(t, int(addRole), false).Status()
+	require.NoError(t, err)
+	require.Equal(t, []migrate.MigrationStatus{
+		{Version: createUsers, Name: "create_users", CreatedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), Applied: true},
+		{Version: addRole, Name: "add_role", CreatedAt: time.Date(2024, 1, 2, 11, 0, 0, 0, time.UTC), Applied: true},
+		{Version: addIndex, Name: "add_index", CreatedAt: time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC), Applied: false},
+	}, statuses)
+
+	statuses, err = newMigrator(t, database.NilVersion, false).Status()
+	require.NoError(t, err)
+	for _, s := range statuses {
+		require.False(t, s.Applied)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	up := migrate.DirectionUp
+	down := migrate.DirectionDown
+
+	tests := []struct {
+		name    string
+		current int
+		plan    func(m *migrate.Migrator) ([]migrate.PlannedMigration, error)
+		want    []step
+	}{
+		{
+			name:    "up from empty database",
+			current: database.NilVersion,
+			plan:    (*migrate.Migrator).PlanUp,
+			want:    []step{{createUsers, up}, {addRole, up}, {addIndex, up}},
+		},
+		{
+			name:    "up when all applied",
+			current: int(addIndex),
+			plan:    (*migrate.Migrator).PlanUp,
+			want:    []step{},
+		},
+		{
+			name:    "down rolls back everything in reverse order",
+			current: int(addRole),
+			plan:    (*migrate.Migrator).PlanDown,
+			want:    []step{{addRole, down}, {createUsers, down}},
+		},
+		{
+			name:    "steps forward",
+			current: int(createUsers),
+			plan:    func(m *migrate.Migrator) ([]migrate.PlannedMigration, error) { return m.PlanSteps(1) },
+			want:    []step{{addRole, up}},
+		},
+		{
+			name:    "steps forward past the last migration",
+			current: int(addRole),
+			plan:    func(m *migrate.Migrator) ([]migrate.PlannedMigration, error) { return m.PlanSteps(5) },
+			want:    []step{{addIndex, up}},
+		},
+		{
+			name:    "steps back past the first migration",
+			current: int(addRole),
+			plan:    func(m *migrate.Migrator) ([]migrate.PlannedMigration, error) { return m.PlanSteps(-5) },
+			want:    []step{{addRole, down}, {createUsers, down}},
+		},
+		{
+			name:    "goto newer version",
+			current: database.NilVersion,
+			plan:    func(m *migrate.Migrator) ([]migrate.PlannedMigration, error) { return m.PlanGoto(addRole) },
+			want:    []step{{createUsers, up}, {addRole, up}},
+		},
+		{
+			name:    "goto older version",
+			current: int(addIndex),
+			plan:    func(m *migrate.Migrator) ([]migrate.PlannedMigration, error) { return m.PlanGoto(createUsers) },
+			want:    []step{{addIndex, down}, {addRole, down}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			planned, err := tt.plan(newMigrator(t, tt.current, false))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, steps(planned))
+		})
+	}
+}
+
+func TestPlanReadsMigrationSQL(t *testing.T) {
+	t.Parallel()
+
+	planned, err := newMigrator(t, int(addIndex), false).PlanSteps(-1)
+	require.NoError(t, err)
+	require.Equal(t, []migrate.PlannedMigration{{
+		Version:   addIndex,
+		Name:      "add_index",
+		Direction: migrate.DirectionDown,
+		SQL:       "-- down 20240103120000_add_index",
+	}}, planned)
+}
+
+func TestPlanErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := newMigrator(t, database.NilVersion, false).PlanGoto(20240105000000)
+	require.ErrorContains(t, err, "migration version 20240105000000 not found")
+
+	_, err = newMigrator(t, int(addRole), true).PlanUp()
+	require.ErrorContains(t, err, "database version 20240102110000 is dirty")
+
+	_, err = newMigrator(t, 20231231000000, false).PlanDown()
+	require.ErrorContains(t, err, "applied version 20231231000000 not found in migrations")
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "migrations")
+	now := time.Date(2024, 3, 5, 7, 8, 9, 0, time.FixedZone("MSK", 3*60*60))
+
+	// Версия — время в UTC, пробелы в имени заменяются подчеркиваниями
+	upFile, downFile, err := migrate.Create(dir, "Add  User Roles", now)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "20240305040809_add_user_roles.up.sql"), upFile)
+	require.Equal(t, filepath.Join(dir, "20240305040809_add_user_roles.down.sql"), downFile)
+	require.FileExists(t, upFile)
+	require.FileExists(t, downFile)
+
+	_, _, err = migrate.Create(dir, "drop users;", now)
+	require.ErrorContains(t, err, `invalid migration name "drop_users;"`)
+}
+
+func TestCreateDoesNotOverwriteMigrations(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	now := time.Date(2024, 3, 5, 4, 8, 9, 0, time.UTC)
+
+	upFile, _, err := migrate.Create(dir, "add_roles", now)
+	require.NoError(t, err)
+	writeFile(t, upFile, "ALTER TABLE users ADD COLUMN role TEXT;")
+
+	// Повтор с той же версией не трогает существующие файлы
+	_, _, err = migrate.Create(dir, "add_roles", now)
+	require.ErrorContains(t, err, "already exists")
+	body, err := os.ReadFile(upFile)
+	require.NoError(t, err)
+	require.Equal(t, "ALTER TABLE users ADD COLUMN role TEXT;", string(body))
+
+	// Если занят только down файл, созданный up файл удаляется
+	writeFile(t, filepath.Join(dir, "20240305040809_add_index.down.sql"), "")
+	_, _, err = migrate.Create(dir, "add_index", now)
+	require.ErrorContains(t, err, "20240305040809_add_index.down.sql already exists")
+	require.NoFileExists(t, filepath.Join(dir, "20240305040809_add_index.up.sql"))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
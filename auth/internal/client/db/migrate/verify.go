@@ -0,0 +1,164 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// schemaQueries описывают схему текущей БД построчно; таблица версий golang-migrate исключена
+var schemaQueries = []string{
+	`SELECT format('column %s.%s %s nullable=%s default=%s', table_name, column_name, data_type, is_nullable, coalesce(column_default, ''))
+	FROM information_schema.columns
+	WHERE table_schema = current_schema() AND table_name <> 'schema_migrations'`,
+	`SELECT format('index %s', indexdef)
+	FROM pg_indexes
+	WHERE schemaname = current_schema() AND tablename <> 'schema_migrations'`,
+	`SELECT format('constraint %s.%s %s', rel.relname, con.conname, pg_get_constraintdef(con.oid))
+	FROM pg_constraint con
+	JOIN pg_class rel ON rel.oid = con.conrelid
+	JOIN pg_namespace ns ON ns.oid = rel.relnamespace
+	WHERE ns.nspname = current_schema() AND rel.relname <> 'schema_migrations'`,
+	`SELECT format('sequence %s', sequence_name)
+	FROM information_schema.sequences
+	WHERE sequence_schema = current_schema()`,
+	`SELECT format('view %s %s', table_name, view_definition)
+	FROM information_schema.views
+	WHERE table_schema = current_schema()`,
+	`SELECT format('type %s %s', t.typname, string_agg(e.enumlabel, ',' ORDER BY e.enumsortorder))
+	FROM pg_type t
+	JOIN pg_enum e ON e.enumtypid = t.oid
+	JOIN pg_namespace ns ON ns.oid = t.typnamespace
+	WHERE ns.nspname = current_schema()
+	GROUP BY t.typname`,
+	`SELECT format('function %s(%s)', p.proname, pg_get_function_identity_arguments(p.oid))
+	FROM pg_proc p
+	JOIN pg_namespace ns ON ns.oid = p.pronamespace
+	WHERE ns.nspname = current_schema()`,
+	`SELECT format('trigger %s.%s', event_object_table, trigger_name)
+	FROM information_schema.triggers
+	WHERE trigger_schema = current_schema()`,
+}
+
+// Verify проверяет обратимость миграций на пустой (scratch) БД: каждая миграция
+// применяется, откатывается и применяется снова, а схема после отката и повторного
+// применения сравнивается со схемой до и после миграции соответственно.
+// По завершении все миграции остаются применёнными.
+func (m *Migrator) Verify(ctx context.Context) error {
+	if m.db == nil {
+		return fmt.Errorf("verify requires a postgres connection")
+	}
+
+	_, _, ok, err := m.currentVersion()
+	if err != nil {
+		return err
+	}
+	if ok {
+		return fmt.Errorf("verify must run against an empty scratch database, but migrations are already applied")
+	}
+
+	versions, err := m.versions()
+	if err != nil {
+		return err
+	}
+
+	before, err := m.schema(ctx)
+	if err != nil {
+		return err
+	}
+
+	var after, reverted, reapplied []string
+	for _, v := range versions {
+		if err = m.migrate.Steps(1); err != nil {
+			return fmt.Errorf("migration %d up failed: %w", v, err)
+		}
+		after, err = m.schema(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err = m.migrate.Steps(-1); err != nil {
+			return fmt.Errorf("migration %d down failed: %w", v, err)
+		}
+		reverted, err = m.schema(ctx)
+		if err != nil {
+			return err
+		}
+		if diff := schemaDiff(before, reverted); diff != "" {
+			return fmt.Errorf("migration %d down does not restore the previous schema:\n%s", v, diff)
+		}
+
+		if err = m.migrate.Steps(1); err != nil {
+			return fmt.Errorf("migration %d up after down failed: %w", v, err)
+		}
+		reapplied, err = m.schema(ctx)
+		if err != nil {
+			return err
+		}
+		if diff := schemaDiff(after, reapplied); diff != "" {
+			return fmt.Errorf("migration %d up after down produces a different schema:\n%s", v, diff)
+		}
+
+		log.Printf("Migration %d is reversible", v)
+		before = after
+	}
+
+	return nil
+}
+
+// schema возвращает отсортированное построчное описание схемы
+func (m *Migrator) schema(ctx context.Context) ([]string, error) {
+	var lines []string
+
+	for _, q := range schemaQueries {
+		rows, err := m.db.QueryContext(ctx, q)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+
+		for rows.Next() {
+			var line string
+			if err = rows.Scan(&line); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to read schema: %w", err)
+			}
+			lines = append(lines, line)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+	}
+
+	sort.Strings(lines)
+	return lines, nil
+}
+
+// schemaDiff возвращает строки, отличающиеся в want и got, в формате "- want" / "+ got"
+func schemaDiff(want, got []string) string {
+	wantSet := make(map[string]bool, len(want))
+	for _, l := range want {
+		wantSet[l] = true
+	}
+	gotSet := make(map[string]bool, len(got))
+	for _, l := range got {
+		gotSet[l] = true
+	}
+
+	var diff []string
+	for _, l := range want {
+		if !gotSet[l] {
+			diff = append(diff, "- "+l)
+		}
+	}
+	for _, l := range got {
+		if !wantSet[l] {
+			diff = append(diff, "+ "+l)
+		}
+	}
+
+	return strings.Join(diff, "\n")
+}
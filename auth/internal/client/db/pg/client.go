@@ -13,14 +13,29 @@ type pgClient struct {
 	masterDBC db.DB
 }
 
-func New(ctx context.Context, dsn string) (db.Client, error) {
+// Option настраивает клиент БД
+type Option func(p *pg)
+
+// WithQueryHook добавляет хук, вызываемый после каждого запроса
+func WithQueryHook(hook db.QueryHook) Option {
+	return func(p *pg) {
+		p.hooks = append(p.hooks, hook)
+	}
+}
+
+func New(ctx context.Context, dsn string, opts ...Option) (db.Client, error) {
 	dbc, err := pgxpool.Connect(ctx, dsn)
 	if err != nil {
 		return nil, errors.Errorf("failed to connect to db: %v", err)
 	}
 
+	p := &pg{dbc: dbc}
+	for _, opt := range opts {
+		opt(p)
+	}
+
 	return &pgClient{
-		masterDBC: &pg{dbc: dbc},
+		masterDBC: p,
 	}, nil
 }
 
@@ -2,16 +2,17 @@ package pg
 
 import (
 	"context"
-	"fmt"
-	"log"
 
 	"github.com/georgysavva/scany/pgxscan"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/MercerMorning/go_example/auth/internal/client/db"
 	"github.com/MercerMorning/go_example/auth/internal/client/db/prettier"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
 )
 
 type key string
@@ -21,7 +22,8 @@ const (
 )
 
 type pg struct {
-	dbc *pgxpool.Pool
+	dbc   *pgxpool.Pool
+	hooks []db.QueryHook
 }
 
 func NewDB(dbc *pgxpool.Pool) db.DB {
@@ -55,34 +57,60 @@ func (p *pg) ScanAllContext(ctx context.Context, dest interface{}, q db.Query, a
 func (p *pg) ExecContext(ctx context.Context, q db.Query, args ...interface{}) (pgconn.CommandTag, error) {
 	logQuery(ctx, q, args...)
 
+	ctx, query := p.startQuery(ctx, q)
+
+	var (
+		tag pgconn.CommandTag
+		err error
+	)
 	tx, ok := ctx.Value(TxKey).(pgx.Tx)
 	if ok {
-		return tx.Exec(ctx, q.QueryRaw, args...)
+		tag, err = tx.Exec(ctx, q.QueryRaw, args...)
+	} else {
+		tag, err = p.dbc.Exec(ctx, q.QueryRaw, args...)
 	}
+	query.end(err)
 
-	return p.dbc.Exec(ctx, q.QueryRaw, args...)
+	return tag, err
 }
 
 func (p *pg) QueryContext(ctx context.Context, q db.Query, args ...interface{}) (pgx.Rows, error) {
 	logQuery(ctx, q, args...)
 
+	ctx, query := p.startQuery(ctx, q)
+
+	var (
+		rows pgx.Rows
+		err  error
+	)
 	tx, ok := ctx.Value(TxKey).(pgx.Tx)
 	if ok {
-		return tx.Query(ctx, q.QueryRaw, args...)
+		rows, err = tx.Query(ctx, q.QueryRaw, args...)
+	} else {
+		rows, err = p.dbc.Query(ctx, q.QueryRaw, args...)
+	}
+	if err != nil {
+		query.end(err)
+		return nil, err
 	}
 
-	return p.dbc.Query(ctx, q.QueryRaw, args...)
+	return &tracedRows{Rows: rows, query: query}, nil
 }
 
 func (p *pg) QueryRowContext(ctx context.Context, q db.Query, args ...interface{}) pgx.Row {
 	logQuery(ctx, q, args...)
 
+	ctx, query := p.startQuery(ctx, q)
+
+	var row pgx.Row
 	tx, ok := ctx.Value(TxKey).(pgx.Tx)
 	if ok {
-		return tx.QueryRow(ctx, q.QueryRaw, args...)
+		row = tx.QueryRow(ctx, q.QueryRaw, args...)
+	} else {
+		row = p.dbc.QueryRow(ctx, q.QueryRaw, args...)
 	}
 
-	return p.dbc.QueryRow(ctx, q.QueryRaw, args...)
+	return &tracedRow{row: row, query: query}
 }
 
 func (p *pg) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
@@ -102,10 +130,13 @@ func MakeContextTx(ctx context.Context, tx pgx.Tx) context.Context {
 }
 
 func logQuery(ctx context.Context, q db.Query, args ...interface{}) {
+	if !logger.Named(logger.SubsystemDB).Core().Enabled(zapcore.DebugLevel) {
+		return
+	}
+
 	prettyQuery := prettier.Pretty(q.QueryRaw, prettier.PlaceholderDollar, args...)
-	log.Println(
-		ctx,
-		fmt.Sprintf("sql: %s", q.Name),
-		fmt.Sprintf("query: %s", prettyQuery),
+	logger.NamedFromContext(ctx, logger.SubsystemDB).Debug("sql",
+		zap.String("name", q.Name),
+		zap.String("query", prettyQuery),
 	)
 }
@@ -0,0 +1,79 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/MercerMorning/go_example/auth/internal/client/db"
+	"github.com/MercerMorning/go_example/auth/internal/tracing"
+)
+
+// query наблюдает за выполнением запроса: span и хуки завершаются вместе,
+// для Query и QueryRow — при закрытии результата или чтении строки
+type query struct {
+	ctx   context.Context
+	q     db.Query
+	start time.Time
+	span  trace.Span
+	hooks []db.QueryHook
+}
+
+// startQuery открывает span запроса. Текст запроса пишется без аргументов, чтобы
+// значения параметров не попадали в трейсы.
+func (p *pg) startQuery(ctx context.Context, q db.Query) (context.Context, *query) {
+	ctx, span := tracing.Tracer().Start(ctx, q.Name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBQueryText(q.QueryRaw),
+		),
+	)
+
+	return ctx, &query{ctx: ctx, q: q, start: time.Now(), span: span, hooks: p.hooks}
+}
+
+func (o *query) end(err error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+	}
+	o.span.End()
+
+	duration := time.Since(o.start)
+	for _, hook := range o.hooks {
+		hook(o.ctx, o.q, duration, err)
+	}
+}
+
+// tracedRows завершает запрос при закрытии результата
+type tracedRows struct {
+	pgx.Rows
+	query *query
+}
+
+func (r *tracedRows) Close() {
+	r.Rows.Close()
+	r.query.end(r.Rows.Err())
+}
+
+// tracedRow завершает запрос при чтении строки
+type tracedRow struct {
+	row   pgx.Row
+	query *query
+}
+
+func (r *tracedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	r.query.end(err)
+
+	return err
+}
@@ -1,12 +1,55 @@
 package closer
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"sync"
+	"time"
 )
 
+// Phase defines the order of shutdown: phases are closed one after another,
+// closers within a phase are closed concurrently
+type Phase int
+
+const (
+	// PhaseServers stops accepting and finishes in-flight requests
+	PhaseServers Phase = iota
+	// PhaseWorkers stops background workers
+	PhaseWorkers
+	// PhaseClients closes outbound connections
+	PhaseClients
+	// PhaseDB closes database connections
+	PhaseDB
+	// PhaseTelemetry flushes traces and other telemetry collected during shutdown
+	PhaseTelemetry
+
+	phaseCount
+)
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseServers:
+		return "servers"
+	case PhaseWorkers:
+		return "workers"
+	case PhaseClients:
+		return "clients"
+	case PhaseDB:
+		return "db"
+	case PhaseTelemetry:
+		return "telemetry"
+	default:
+		return fmt.Sprintf("phase %d", int(p))
+	}
+}
+
+// DefaultTimeout is the time a single closer is given when no timeout is set
+const DefaultTimeout = 5 * time.Second
+
 var globalCloser = New()
 
 // Add adds `func() error` callback to the globalCloser
@@ -14,6 +57,16 @@ func Add(f ...func() error) {
 	globalCloser.Add(f...)
 }
 
+// Register adds a named closer to the phase of the globalCloser
+func Register(phase Phase, name string, f func(ctx context.Context) error) {
+	globalCloser.Register(phase, name, f)
+}
+
+// SetTimeout sets the per-closer timeout of the globalCloser
+func SetTimeout(d time.Duration) {
+	globalCloser.SetTimeout(d)
+}
+
 // Wait ...
 func Wait() {
 	globalCloser.Wait()
@@ -24,17 +77,29 @@ func CloseAll() {
 	globalCloser.CloseAll()
 }
 
+// Shutdown closes the globalCloser phase by phase and returns the aggregated errors
+func Shutdown(ctx context.Context) error {
+	return globalCloser.Shutdown(ctx)
+}
+
+type closeFunc struct {
+	name string
+	f    func(ctx context.Context) error
+}
+
 // Closer ...
 type Closer struct {
-	mu    sync.Mutex
-	once  sync.Once
-	done  chan struct{}
-	funcs []func() error
+	mu      sync.Mutex
+	once    sync.Once
+	done    chan struct{}
+	err     error
+	timeout time.Duration
+	phases  [phaseCount][]closeFunc
 }
 
 // New returns new Closer, if []os.Signal is specified Closer will automatically call CloseAll when one of signals is received from OS
 func New(sig ...os.Signal) *Closer {
-	c := &Closer{done: make(chan struct{})}
+	c := &Closer{done: make(chan struct{}), timeout: DefaultTimeout}
 	if len(sig) > 0 {
 		go func() {
 			ch := make(chan os.Signal, 1)
@@ -47,10 +112,38 @@ func New(sig ...os.Signal) *Closer {
 	return c
 }
 
-// Add func to closer
+// Add func to closer. Unnamed funcs are closed in the PhaseClients phase.
 func (c *Closer) Add(f ...func() error) {
+	for _, fn := range f {
+		c.mu.Lock()
+		name := fmt.Sprintf("closer #%d", len(c.phases[PhaseClients])+1)
+		c.mu.Unlock()
+
+		c.Register(PhaseClients, name, func(context.Context) error {
+			return fn()
+		})
+	}
+}
+
+// Register adds a named func to the phase. The func gets a context that expires after the per-closer timeout.
+func (c *Closer) Register(phase Phase, name string, f func(ctx context.Context) error) {
+	if phase < 0 || phase >= phaseCount {
+		phase = PhaseClients
+	}
+
 	c.mu.Lock()
-	c.funcs = append(c.funcs, f...)
+	c.phases[phase] = append(c.phases[phase], closeFunc{name: name, f: f})
+	c.mu.Unlock()
+}
+
+// SetTimeout sets the time a single closer is given. Zero or negative means DefaultTimeout.
+func (c *Closer) SetTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultTimeout
+	}
+
+	c.mu.Lock()
+	c.timeout = d
 	c.mu.Unlock()
 }
 
@@ -59,28 +152,76 @@ func (c *Closer) Wait() {
 	<-c.done
 }
 
-// CloseAll calls all closer functions
+// CloseAll calls all closer functions and logs the errors
 func (c *Closer) CloseAll() {
+	if err := c.Shutdown(context.Background()); err != nil {
+		log.Printf("errors returned from closers: %v", err)
+	}
+}
+
+// Shutdown calls all closer functions phase by phase. Closers within a phase run concurrently,
+// each is limited by the per-closer timeout and by ctx. Errors are joined and prefixed
+// with the phase and closer name. Repeated calls return the result of the first one.
+func (c *Closer) Shutdown(ctx context.Context) error {
 	c.once.Do(func() {
 		defer close(c.done)
 
 		c.mu.Lock()
-		funcs := c.funcs
-		c.funcs = nil
+		phases := c.phases
+		c.phases = [phaseCount][]closeFunc{}
+		timeout := c.timeout
 		c.mu.Unlock()
 
-		// call all Closer funcs async
-		errs := make(chan error, len(funcs))
-		for _, f := range funcs {
-			go func(f func() error) {
-				errs <- f()
-			}(f)
+		var errs []error
+		for phase, funcs := range phases {
+			errs = append(errs, closePhase(ctx, Phase(phase), funcs, timeout)...)
 		}
 
-		for i := 0; i < cap(errs); i++ {
-			if err := <-errs; err != nil {
-				log.Println("error returned from Closer")
-			}
-		}
+		c.err = errors.Join(errs...)
 	})
+
+	<-c.done
+	return c.err
+}
+
+func closePhase(ctx context.Context, phase Phase, funcs []closeFunc, timeout time.Duration) []error {
+	errs := make([]error, len(funcs))
+
+	var wg sync.WaitGroup
+	for i, cf := range funcs {
+		wg.Add(1)
+		go func(i int, cf closeFunc) {
+			defer wg.Done()
+
+			if err := closeOne(ctx, cf, timeout); err != nil {
+				errs[i] = fmt.Errorf("%s/%s: %w", phase, cf.name, err)
+			}
+		}(i, cf)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// closeOne does not wait for a closer that ignores its context longer than the timeout
+func closeOne(ctx context.Context, cf closeFunc, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("panic: %v", p)
+			}
+		}()
+		done <- cf.f(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("not closed in time: %w", ctx.Err())
+	}
 }
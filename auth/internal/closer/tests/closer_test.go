@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MercerMorning/go_example/auth/internal/closer"
+)
+
+func TestShutdownClosesPhasesInOrder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	record := func(name string) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	c := closer.New()
+	c.Register(closer.PhaseDB, "postgres", record("postgres"))
+	c.Register(closer.PhaseClients, "other_service", record("other_service"))
+	c.Register(closer.PhaseServers, "grpc_server", record("grpc_server"))
+	c.Register(closer.PhaseWorkers, "outbox", record("outbox"))
+
+	require.NoError(t, c.Shutdown(context.Background()))
+	require.Equal(t, []string{"grpc_server", "outbox", "other_service", "postgres"}, order)
+
+	// Повторный вызов не закрывает ресурсы еще раз
+	require.NoError(t, c.Shutdown(context.Background()))
+	require.Len(t, order, 4)
+}
+
+func TestShutdownAggregatesNamedErrors(t *testing.T) {
+	t.Parallel()
+
+	errClose := errors.New("connection reset")
+
+	c := closer.New()
+	c.SetTimeout(50 * time.Millisecond)
+	c.Register(closer.PhaseServers, "http_server", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	c.Register(closer.PhaseClients, "hung", func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	c.Register(closer.PhaseDB, "postgres", func(context.Context) error {
+		return errClose
+	})
+
+	start := time.Now()
+	err := c.Shutdown(context.Background())
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+
+	require.ErrorIs(t, err, errClose)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "servers/http_server:")
+	require.ErrorContains(t, err, "clients/hung: not closed in time")
+	require.ErrorContains(t, err, "db/postgres: connection reset")
+}
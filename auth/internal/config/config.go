@@ -1,9 +1,22 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/joho/godotenv"
+	"go.uber.org/zap/zapcore"
 )
 
+var metricNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Load загружает переменные окружения из .env файла в окружение процесса
 func Load(path string) error {
 	err := godotenv.Load(path)
 	if err != nil {
@@ -12,3 +25,663 @@ func Load(path string) error {
 
 	return nil
 }
+
+// Config конфигурация сервиса.
+//
+// Значения собираются из источников в порядке возрастания приоритета:
+// значения по умолчанию, YAML файл (-config), .env файл (-env-file),
+// переменные окружения (тег env) и флаги командной строки.
+// Имя флага строится из пути в YAML: pg.dsn -> -pg-dsn.
+type Config struct {
+	PG           PG           `yaml:"pg"`
+	GRPC         Listener     `yaml:"grpc" env:"GRPC"`
+	HTTP         Listener     `yaml:"http" env:"HTTP"`
+	Metrics      Metrics      `yaml:"metrics"`
+	Tracing      Tracing      `yaml:"tracing"`
+	Logger       Logger       `yaml:"logger"`
+	Sentry       SentryConfig `yaml:"sentry"`
+	Reporting    Reporting    `yaml:"error_reporting"`
+	OtherService Client       `yaml:"other_service"`
+	Gateway      Gateway      `yaml:"gateway"`
+	Swagger      Swagger      `yaml:"swagger"`
+	RateLimit    RateLimit    `yaml:"rate_limit"`
+	CORS         CORS         `yaml:"cors"`
+	HTTPSecurity HTTPSecurity `yaml:"http_security"`
+	Auth         Auth         `yaml:"auth"`
+	Reload       Reload       `yaml:"reload"`
+	Redaction    Redaction    `yaml:"redaction"`
+	Health       Health       `yaml:"health"`
+	Shutdown     Shutdown     `yaml:"shutdown"`
+	Startup      Startup      `yaml:"startup"`
+}
+
+// PG настройки подключения к PostgreSQL
+type PG struct {
+	DSN string `yaml:"dsn" env:"PG_DSN" secret:"dsn" usage:"PostgreSQL DSN"`
+}
+
+// Listener адрес, на котором сервер принимает соединения.
+// Переменные окружения строятся из префикса секции: GRPC_HOST, HTTP_PORT.
+type Listener struct {
+	Host string `yaml:"host" env:"_HOST" usage:"listen host"`
+	Port string `yaml:"port" env:"_PORT" usage:"listen port"`
+
+	TLS ServerTLS `yaml:"tls" env:"_TLS"`
+}
+
+// ServerTLS настройки TLS сервера. Переменные окружения строятся из префикса секции:
+// GRPC_TLS_CERT_FILE, METRICS_TLS_CLIENT_AUTH. Файлы перечитываются при изменении.
+type ServerTLS struct {
+	Enabled      bool   `yaml:"enabled" env:"_ENABLED" usage:"serve TLS"`
+	CertFile     string `yaml:"cert_file" env:"_CERT_FILE" usage:"PEM certificate chain of the server"`
+	KeyFile      string `yaml:"key_file" env:"_KEY_FILE" usage:"PEM private key of the server"`
+	ClientCAFile string `yaml:"client_ca_file" env:"_CLIENT_CA_FILE" usage:"PEM CA bundle used to verify client certificates"`
+	ClientAuth   string `yaml:"client_auth" env:"_CLIENT_AUTH" usage:"client certificates: none, request (verify if given) or require"`
+	MinVersion   string `yaml:"min_version" env:"_MIN_VERSION" usage:"minimum TLS version: 1.2 or 1.3"`
+}
+
+// ClientTLS настройки TLS исходящего gRPC клиента
+type ClientTLS struct {
+	Enabled    bool   `yaml:"enabled" env:"_ENABLED" usage:"connect with TLS"`
+	CAFile     string `yaml:"ca_file" env:"_CA_FILE" usage:"PEM CA bundle used to verify the server, empty uses system roots"`
+	CertFile   string `yaml:"cert_file" env:"_CERT_FILE" usage:"PEM client certificate chain for mutual TLS"`
+	KeyFile    string `yaml:"key_file" env:"_KEY_FILE" usage:"PEM private key of the client certificate"`
+	ServerName string `yaml:"server_name" env:"_SERVER_NAME" usage:"expected server name, empty uses the host of the address"`
+}
+
+// Metrics настройки сервера метрик Prometheus
+type Metrics struct {
+	Address   string    `yaml:"address" env:"METRICS_ADDRESS" usage:"Prometheus metrics listen address"`
+	Namespace string    `yaml:"namespace" env:"METRICS_NAMESPACE" usage:"prefix of metric names"`
+	Buckets   []float64 `yaml:"buckets" env:"METRICS_BUCKETS" usage:"comma separated latency histogram buckets in seconds"`
+
+	TLS ServerTLS `yaml:"tls" env:"METRICS_TLS"`
+}
+
+// Tracing настройки трейсинга OpenTelemetry
+type Tracing struct {
+	ServiceName    string  `yaml:"service_name" env:"TRACING_SERVICE_NAME" usage:"service.name resource attribute"`
+	ServiceVersion string  `yaml:"service_version" env:"TRACING_SERVICE_VERSION" usage:"service.version resource attribute"`
+	Environment    string  `yaml:"environment" env:"TRACING_ENVIRONMENT" usage:"deployment.environment.name resource attribute"`
+	Exporter       string  `yaml:"exporter" env:"TRACING_EXPORTER" usage:"span exporter: otlp, stdout, file or none"`
+	OTLPEndpoint   string  `yaml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT" usage:"OTLP gRPC collector host:port"`
+	OTLPInsecure   bool    `yaml:"otlp_insecure" env:"TRACING_OTLP_INSECURE" usage:"connect to the OTLP collector without TLS"`
+	FilePath       string  `yaml:"file_path" env:"TRACING_FILE" usage:"file for the file exporter, spans are written as JSON lines"`
+	SampleRatio    float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" usage:"share of new traces to sample, child spans follow the parent decision"`
+}
+
+// Logger настройки логирования в файл
+type Logger struct {
+	Level      string `yaml:"level" env:"LOG_LEVEL" usage:"log level: debug, info, warn, error"`
+	FilePath   string `yaml:"file_path" env:"LOG_FILE" usage:"log file path"`
+	MaxSizeMB  int    `yaml:"max_size_mb" env:"LOG_MAX_SIZE_MB" usage:"max log file size in megabytes before rotation"`
+	MaxBackups int    `yaml:"max_backups" env:"LOG_MAX_BACKUPS" usage:"number of rotated log files to keep"`
+	MaxAgeDays int    `yaml:"max_age_days" env:"LOG_MAX_AGE_DAYS" usage:"days to keep rotated log files"`
+
+	Subsystems LoggerSubsystems `yaml:"subsystems"`
+	Sentry     LoggerSentry     `yaml:"sentry"`
+}
+
+// LoggerSentry отправка записей лога в Reporter из секции error_reporting
+type LoggerSentry struct {
+	Enabled         bool   `yaml:"enabled" env:"LOG_SENTRY_ENABLED" usage:"send log entries to the error reporter"`
+	Level           string `yaml:"level" env:"LOG_SENTRY_LEVEL" usage:"min level of log entries sent as errors"`
+	BreadcrumbLevel string `yaml:"breadcrumb_level" env:"LOG_SENTRY_BREADCRUMB_LEVEL" usage:"min level of log entries recorded as breadcrumbs of the request"`
+	QueueSize       int    `yaml:"queue_size" env:"LOG_SENTRY_QUEUE_SIZE" usage:"log errors waiting to be sent, new ones are dropped when the queue is full"`
+}
+
+// LoggerSubsystems уровни логирования подсистем. Пустое значение — общий уровень logger.level.
+type LoggerSubsystems struct {
+	DB     string `yaml:"db" env:"LOG_LEVEL_DB" usage:"log level of the db subsystem, empty inherits logger.level"`
+	GRPC   string `yaml:"grpc" env:"LOG_LEVEL_GRPC" usage:"log level of the grpc subsystem, empty inherits logger.level"`
+	HTTP   string `yaml:"http" env:"LOG_LEVEL_HTTP" usage:"log level of the http subsystem, empty inherits logger.level"`
+	Outbox string `yaml:"outbox" env:"LOG_LEVEL_OUTBOX" usage:"log level of the outbox subsystem, empty inherits logger.level"`
+}
+
+// Levels возвращает уровни по имени подсистемы
+func (s LoggerSubsystems) Levels() map[string]string {
+	return map[string]string{
+		"db":     s.DB,
+		"grpc":   s.GRPC,
+		"http":   s.HTTP,
+		"outbox": s.Outbox,
+	}
+}
+
+// Reporting куда отправляются ошибки, сообщения и транзакции сервиса
+type Reporting struct {
+	Backend  string        `yaml:"backend" env:"ERROR_REPORTING_BACKEND" usage:"error reporter: sentry, file, http, memory or none"`
+	FilePath string        `yaml:"file_path" env:"ERROR_REPORTING_FILE" usage:"file for the file reporter, events are written as JSON lines"`
+	URL      string        `yaml:"url" env:"ERROR_REPORTING_URL" usage:"endpoint the http reporter posts JSON events to"`
+	Timeout  time.Duration `yaml:"timeout" env:"ERROR_REPORTING_TIMEOUT" usage:"timeout of one request of the http reporter"`
+}
+
+// Client настройки исходящего gRPC клиента
+type Client struct {
+	Address string `yaml:"address" env:"OTHER_SERVICE_ADDRESS" usage:"other_service gRPC address"`
+
+	TLS ClientTLS `yaml:"tls" env:"OTHER_SERVICE_TLS"`
+}
+
+// Gateway настройки подключения HTTP шлюза к собственному gRPC серверу
+type Gateway struct {
+	TLS ClientTLS `yaml:"tls" env:"GATEWAY_TLS"`
+}
+
+// RateLimit ограничение частоты запросов к каждому gRPC методу. RPS = 0 отключает ограничение.
+type RateLimit struct {
+	RPS   float64 `yaml:"rps" env:"RATE_LIMIT_RPS" usage:"allowed requests per second for each gRPC method, 0 disables the limit"`
+	Burst int     `yaml:"burst" env:"RATE_LIMIT_BURST" usage:"maximum burst of requests for each gRPC method"`
+}
+
+// CORS настройки CORS для HTTP шлюза. Источник может содержать одну * в имени хоста,
+// например https://*.example.com, она совпадает с любыми поддоменами.
+type CORS struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" usage:"comma separated list of allowed CORS origins, * in a host matches subdomains"`
+	AllowedMethods   []string      `yaml:"allowed_methods" env:"CORS_ALLOWED_METHODS" usage:"comma separated list of allowed CORS methods"`
+	AllowedHeaders   []string      `yaml:"allowed_headers" env:"CORS_ALLOWED_HEADERS" usage:"comma separated list of allowed request headers"`
+	ExposedHeaders   []string      `yaml:"exposed_headers" env:"CORS_EXPOSED_HEADERS" usage:"comma separated list of response headers readable by the browser"`
+	AllowCredentials bool          `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS" usage:"allow cookies and Authorization, requires explicit origins"`
+	MaxAge           time.Duration `yaml:"max_age" env:"CORS_MAX_AGE" usage:"how long browsers cache preflight responses, 0 uses the browser default"`
+}
+
+// HTTPSecurity заголовки безопасности и ограничения запросов HTTP сервера
+type HTTPSecurity struct {
+	HSTSMaxAge            time.Duration `yaml:"hsts_max_age" env:"HTTP_HSTS_MAX_AGE" usage:"Strict-Transport-Security max-age for HTTPS responses, 0 disables HSTS"`
+	HSTSIncludeSubdomains bool          `yaml:"hsts_include_subdomains" env:"HTTP_HSTS_INCLUDE_SUBDOMAINS" usage:"add includeSubDomains to Strict-Transport-Security"`
+	FrameOptions          string        `yaml:"frame_options" env:"HTTP_FRAME_OPTIONS" usage:"X-Frame-Options: DENY, SAMEORIGIN or empty to omit"`
+	ContentSecurityPolicy string        `yaml:"content_security_policy" env:"HTTP_CONTENT_SECURITY_POLICY" usage:"Content-Security-Policy of API responses, Swagger UI uses its own"`
+	MaxBodySize           int64         `yaml:"max_body_size" env:"HTTP_MAX_BODY_SIZE" usage:"maximum request body size in bytes, 0 disables the limit"`
+}
+
+// Auth статические токены доступа и правила авторизации gRPC методов.
+// Задаются только в YAML файле.
+type Auth struct {
+	Tokens []Token `yaml:"tokens"`
+	Rules  []Rule  `yaml:"rules"`
+}
+
+// Token bearer токен, выданный субъекту с ролью
+type Token struct {
+	Token   string `yaml:"token" secret:"true"`
+	Subject string `yaml:"subject"`
+	Role    string `yaml:"role"`
+}
+
+// Rule разрешает вызов метода только указанным ролям.
+// Method — полное имя метода (/user_v1.UserV1/Delete) или префикс сервиса со звездочкой (/user_v1.UserV1/*).
+// Методы без правил доступны без токена.
+type Rule struct {
+	Method string   `yaml:"method"`
+	Roles  []string `yaml:"roles"`
+}
+
+// Swagger настройки документации API: OpenAPI документа и Swagger UI.
+// Без Port документация отдается HTTP сервером шлюза по пути /swagger/.
+type Swagger struct {
+	Enabled   bool   `yaml:"enabled" env:"SWAGGER_ENABLED" usage:"serve OpenAPI document and Swagger UI, disable in production if the API is private"`
+	Host      string `yaml:"host" env:"SWAGGER_HOST" usage:"listen host of the dedicated Swagger server"`
+	Port      string `yaml:"port" env:"SWAGGER_PORT" usage:"listen port of the dedicated Swagger server, empty serves Swagger from the HTTP server"`
+	APIHost   string `yaml:"api_host" env:"SWAGGER_API_HOST" usage:"host:port of the API in the OpenAPI document, empty uses the request host or the HTTP server address"`
+	BasePath  string `yaml:"base_path" env:"SWAGGER_BASE_PATH" usage:"path prefix of the API behind a proxy, for example /auth"`
+	AssetsURL string `yaml:"assets_url" env:"SWAGGER_UI_ASSETS_URL" usage:"base URL of swagger-ui-dist assets"`
+}
+
+// Address возвращает адрес отдельного сервера Swagger
+func (s Swagger) Address() string {
+	return net.JoinHostPort(s.Host, s.Port)
+}
+
+// Reload настройки перезагрузки конфигурации без перезапуска
+type Reload struct {
+	Interval time.Duration `yaml:"interval" env:"CONFIG_RELOAD_INTERVAL" usage:"how often config files are checked for changes, 0 disables the check (SIGHUP still works)"`
+	// CertInterval не чаще какого интервала при новых соединениях проверяются файлы сертификатов
+	CertInterval time.Duration `yaml:"cert_interval" env:"TLS_RELOAD_INTERVAL" usage:"how often certificate files are checked for changes on new connections, 0 checks on every handshake"`
+}
+
+// Redaction скрытие чувствительных полей в логах, тегах span и контексте Sentry.
+// Поля с опцией (options.sensitive) скрываются всегда, Fields дополняет их.
+type Redaction struct {
+	Fields  []string `yaml:"fields" env:"REDACTION_FIELDS" usage:"comma separated proto field names to redact: password or user_v1.CreateRequest.password"`
+	MaxSize int      `yaml:"max_size" env:"REDACTION_MAX_SIZE" usage:"max size in bytes of a logged payload, longer payloads are truncated"`
+}
+
+// Health настройки проверок готовности (/readyz и grpc.health.v1)
+type Health struct {
+	Timeout  time.Duration `yaml:"timeout" env:"HEALTH_CHECK_TIMEOUT" usage:"timeout of a single readiness check"`
+	CacheTTL time.Duration `yaml:"cache_ttl" env:"HEALTH_CACHE_TTL" usage:"how long a readiness check result is reused"`
+	Interval time.Duration `yaml:"interval" env:"HEALTH_CHECK_INTERVAL" usage:"how often readiness is re-evaluated for the gRPC health service"`
+}
+
+// Shutdown настройки плавного завершения по SIGINT/SIGTERM
+type Shutdown struct {
+	Timeout       time.Duration `yaml:"timeout" env:"SHUTDOWN_TIMEOUT" usage:"deadline for the whole shutdown"`
+	CloserTimeout time.Duration `yaml:"closer_timeout" env:"SHUTDOWN_CLOSER_TIMEOUT" usage:"deadline for a single resource to close"`
+	DrainDelay    time.Duration `yaml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY" usage:"pause between NOT_SERVING and stopping the servers, lets load balancers notice"`
+}
+
+// Startup повторные попытки подключения к зависимостям при старте:
+// задержка между попытками растет от Backoff в два раза до MaxBackoff
+type Startup struct {
+	Attempts       int           `yaml:"attempts" env:"STARTUP_ATTEMPTS" usage:"attempts to connect to PostgreSQL and other_service at startup"`
+	AttemptTimeout time.Duration `yaml:"attempt_timeout" env:"STARTUP_ATTEMPT_TIMEOUT" usage:"timeout of a single connection attempt"`
+	Backoff        time.Duration `yaml:"backoff" env:"STARTUP_BACKOFF" usage:"delay before the second attempt, doubled after each failure"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env:"STARTUP_MAX_BACKOFF" usage:"maximum delay between attempts"`
+}
+
+// Reloadable подмножество конфигурации, которое применяется без перезапуска сервиса
+type Reloadable struct {
+	LogLevel               string
+	LogSubsystems          LoggerSubsystems
+	RateLimit              RateLimit
+	SentrySampleRate       float64
+	SentryTracesSampleRate float64
+	CORS                   CORS
+	Auth                   Auth
+}
+
+// Default возвращает конфигурацию со значениями по умолчанию
+func Default() *Config {
+	return &Config{
+		GRPC: Listener{
+			Host: "localhost",
+			Port: "50051",
+		},
+		HTTP: Listener{
+			Host: "localhost",
+			Port: "8080",
+		},
+		Metrics: Metrics{
+			Address:   "localhost:2112",
+			Namespace: "auth",
+			Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+		Tracing: Tracing{
+			ServiceName:    "auth",
+			ServiceVersion: "dev",
+			Environment:    "development",
+			Exporter:       "otlp",
+			OTLPEndpoint:   "localhost:4317",
+			OTLPInsecure:   true,
+			FilePath:       "logs/traces.json",
+			SampleRatio:    1.0,
+		},
+		Logger: Logger{
+			Level:      "info",
+			FilePath:   "logs/app.log",
+			MaxSizeMB:  10,
+			MaxBackups: 3,
+			MaxAgeDays: 7,
+			Sentry: LoggerSentry{
+				Level:           "error",
+				BreadcrumbLevel: "info",
+				QueueSize:       1000,
+			},
+		},
+		Sentry: SentryConfig{
+			Environment:      "development",
+			SampleRate:       1.0,
+			TracesSampleRate: 0.1,
+			ScrubFields:      []string{"password", "passwd", "secret", "token", "authorization", "cookie", "api_key", "apikey"},
+			DedupWindow:      time.Minute,
+			DedupLimit:       10,
+			IgnoreGRPCCodes:  []string{"Canceled", "DeadlineExceeded"},
+			Service:          "auth-service",
+			MaxSpans:         1000,
+			SlowThreshold: SentrySlowThreshold{
+				Request: 100 * time.Millisecond,
+				Stream:  500 * time.Millisecond,
+				Query:   time.Second,
+			},
+		},
+		Reporting: Reporting{
+			Backend:  "sentry",
+			FilePath: "logs/errors.json",
+			Timeout:  5 * time.Second,
+		},
+		OtherService: Client{
+			Address: "localhost:50052",
+		},
+		CORS: CORS{
+			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			AllowedHeaders: []string{"Accept", "Content-Type", "Authorization", "X-Request-ID", "Idempotency-Key"},
+			ExposedHeaders: []string{"X-Request-ID"},
+			MaxAge:         10 * time.Minute,
+		},
+		HTTPSecurity: HTTPSecurity{
+			HSTSMaxAge:            365 * 24 * time.Hour,
+			FrameOptions:          "DENY",
+			ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+			MaxBodySize:           1 << 20,
+		},
+		Swagger: Swagger{
+			Enabled:   true,
+			Host:      "localhost",
+			AssetsURL: "https://unpkg.com/swagger-ui-dist@5",
+		},
+		Reload: Reload{
+			Interval:     10 * time.Second,
+			CertInterval: 10 * time.Second,
+		},
+		Redaction: Redaction{
+			MaxSize: 4096,
+		},
+		Health: Health{
+			Timeout:  2 * time.Second,
+			CacheTTL: 5 * time.Second,
+			Interval: 10 * time.Second,
+		},
+		Shutdown: Shutdown{
+			Timeout:       30 * time.Second,
+			CloserTimeout: 10 * time.Second,
+		},
+		Startup: Startup{
+			Attempts:       3,
+			AttemptTimeout: 2 * time.Second,
+			Backoff:        500 * time.Millisecond,
+			MaxBackoff:     5 * time.Second,
+		},
+	}
+}
+
+// Validate проверяет конфигурацию и возвращает все найденные проблемы одной ошибкой
+func (c *Config) Validate() error {
+	var problems []error
+	check := func(ok bool, field, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	check(strings.TrimSpace(c.PG.DSN) != "", "pg.dsn", "is required")
+
+	check(c.GRPC.Host != "", "grpc.host", "is required")
+	check(validPort(c.GRPC.Port), "grpc.port", "must be a number between 1 and 65535, got %q", c.GRPC.Port)
+	check(c.HTTP.Host != "", "http.host", "is required")
+	check(validPort(c.HTTP.Port), "http.port", "must be a number between 1 and 65535, got %q", c.HTTP.Port)
+	check(c.GRPC.Address() != c.HTTP.Address(), "http.port", "must differ from grpc.port on the same host")
+
+	check(validAddress(c.Metrics.Address), "metrics.address", "must be host:port, got %q", c.Metrics.Address)
+	check(c.Metrics.Address != c.HTTP.Address() && c.Metrics.Address != c.GRPC.Address(),
+		"metrics.address", "must differ from grpc and http addresses")
+	check(metricNameRegex.MatchString(c.Metrics.Namespace), "metrics.namespace", "must match %s, got %q", metricNameRegex, c.Metrics.Namespace)
+	check(len(c.Metrics.Buckets) > 0, "metrics.buckets", "must not be empty")
+	for i := 1; i < len(c.Metrics.Buckets); i++ {
+		if c.Metrics.Buckets[i] <= c.Metrics.Buckets[i-1] {
+			check(false, "metrics.buckets", "must be sorted in increasing order")
+			break
+		}
+	}
+
+	check(c.Tracing.ServiceName != "", "tracing.service_name", "is required")
+	switch c.Tracing.Exporter {
+	case "otlp":
+		check(validAddress(c.Tracing.OTLPEndpoint), "tracing.otlp_endpoint", "must be host:port, got %q", c.Tracing.OTLPEndpoint)
+	case "file":
+		check(c.Tracing.FilePath != "", "tracing.file_path", "is required for the file exporter")
+	case "stdout", "none":
+	default:
+		check(false, "tracing.exporter", "must be one of otlp, stdout, file, none, got %q", c.Tracing.Exporter)
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1")
+
+	_, err := zapcore.ParseLevel(c.Logger.Level)
+	check(err == nil, "logger.level", "unknown level %q", c.Logger.Level)
+	for name, level := range c.Logger.Subsystems.Levels() {
+		if level == "" {
+			continue
+		}
+		_, err = zapcore.ParseLevel(level)
+		check(err == nil, "logger.subsystems."+name, "unknown level %q", level)
+	}
+	check(c.Logger.FilePath != "", "logger.file_path", "is required")
+	check(c.Logger.MaxSizeMB > 0, "logger.max_size_mb", "must be positive")
+	check(c.Logger.MaxBackups >= 0, "logger.max_backups", "must not be negative")
+	check(c.Logger.MaxAgeDays >= 0, "logger.max_age_days", "must not be negative")
+	if c.Logger.Sentry.Enabled {
+		level, levelErr := zapcore.ParseLevel(c.Logger.Sentry.Level)
+		check(levelErr == nil, "logger.sentry.level", "unknown level %q", c.Logger.Sentry.Level)
+		breadcrumbLevel, breadcrumbErr := zapcore.ParseLevel(c.Logger.Sentry.BreadcrumbLevel)
+		check(breadcrumbErr == nil, "logger.sentry.breadcrumb_level", "unknown level %q", c.Logger.Sentry.BreadcrumbLevel)
+		check(levelErr != nil || breadcrumbErr != nil || breadcrumbLevel <= level,
+			"logger.sentry.breadcrumb_level", "must not be above logger.sentry.level")
+		check(c.Logger.Sentry.QueueSize > 0, "logger.sentry.queue_size", "must be positive")
+	}
+
+	check(c.Sentry.SampleRate >= 0 && c.Sentry.SampleRate <= 1, "sentry.sample_rate", "must be between 0 and 1")
+	check(c.Sentry.TracesSampleRate >= 0 && c.Sentry.TracesSampleRate <= 1, "sentry.traces_sample_rate", "must be between 0 and 1")
+	for i, pattern := range c.Sentry.ScrubPatterns {
+		_, err := regexp.Compile(pattern)
+		check(err == nil, fmt.Sprintf("sentry.scrub_patterns[%d]", i), "invalid regular expression: %v", err)
+	}
+	check(c.Sentry.DedupWindow >= 0, "sentry.dedup_window", "must not be negative")
+	check(c.Sentry.DedupWindow == 0 || c.Sentry.DedupLimit > 0, "sentry.dedup_limit", "must be positive when sentry.dedup_window is set")
+	for i, name := range c.Sentry.IgnoreGRPCCodes {
+		_, ok := GRPCCode(name)
+		check(ok, fmt.Sprintf("sentry.ignore_grpc_codes[%d]", i), "unknown gRPC code %q", name)
+	}
+	check(c.Sentry.Service != "", "sentry.service", "is required")
+	for i, tag := range c.Sentry.AdditionalTags {
+		_, _, ok := SentryTag(tag)
+		check(ok, fmt.Sprintf("sentry.additional_tags[%d]", i), "must be key:value, got %q", tag)
+	}
+	check(c.Sentry.MaxBreadcrumbs >= 0 && c.Sentry.MaxBreadcrumbs <= 100, "sentry.max_breadcrumbs", "must be between 0 and 100")
+	check(c.Sentry.MaxSpans > 0, "sentry.max_spans", "must be positive")
+	check(c.Sentry.FlushTimeout >= 0, "sentry.flush_timeout", "must not be negative")
+	check(c.Sentry.SlowThreshold.Request >= 0, "sentry.slow_threshold.request", "must not be negative")
+	check(c.Sentry.SlowThreshold.Stream >= 0, "sentry.slow_threshold.stream", "must not be negative")
+	check(c.Sentry.SlowThreshold.Query >= 0, "sentry.slow_threshold.query", "must not be negative")
+
+	switch c.Reporting.Backend {
+	case "file":
+		check(c.Reporting.FilePath != "", "error_reporting.file_path", "is required for the file reporter")
+	case "http":
+		check(validBaseURL(c.Reporting.URL), "error_reporting.url", "must be an http(s) URL, got %q", c.Reporting.URL)
+		check(c.Reporting.Timeout > 0, "error_reporting.timeout", "must be positive")
+	case "sentry", "memory", "none":
+	default:
+		check(false, "error_reporting.backend", "must be one of sentry, file, http, memory, none, got %q", c.Reporting.Backend)
+	}
+
+	check(validAddress(c.OtherService.Address), "other_service.address", "must be host:port, got %q", c.OtherService.Address)
+
+	problems = append(problems, c.GRPC.TLS.validate("grpc.tls")...)
+	problems = append(problems, c.HTTP.TLS.validate("http.tls")...)
+	problems = append(problems, c.Metrics.TLS.validate("metrics.tls")...)
+	problems = append(problems, c.OtherService.TLS.validate("other_service.tls")...)
+	problems = append(problems, c.Gateway.TLS.validate("gateway.tls")...)
+	// Шлюз подключается к собственному gRPC серверу и должен говорить с ним на том же протоколе
+	check(c.Gateway.TLS.Enabled == c.GRPC.TLS.Enabled, "gateway.tls.enabled", "must match grpc.tls.enabled")
+	check(c.GRPC.TLS.ClientAuth != "require" || c.Gateway.TLS.CertFile != "",
+		"gateway.tls.cert_file", "is required when grpc.tls.client_auth is require")
+	if c.Swagger.Enabled {
+		if c.Swagger.Port != "" {
+			check(c.Swagger.Host != "", "swagger.host", "is required")
+			check(validPort(c.Swagger.Port), "swagger.port", "must be a number between 1 and 65535, got %q", c.Swagger.Port)
+			check(c.Swagger.Address() != c.HTTP.Address() && c.Swagger.Address() != c.GRPC.Address() && c.Swagger.Address() != c.Metrics.Address,
+				"swagger.port", "must differ from grpc, http and metrics addresses")
+		}
+		check(c.Swagger.APIHost == "" || validAddress(c.Swagger.APIHost), "swagger.api_host", "must be host:port, got %q", c.Swagger.APIHost)
+		check(c.Swagger.BasePath == "" || strings.HasPrefix(c.Swagger.BasePath, "/"), "swagger.base_path", "must start with /, got %q", c.Swagger.BasePath)
+		check(validBaseURL(c.Swagger.AssetsURL), "swagger.assets_url", "must be an absolute http(s) URL, got %q", c.Swagger.AssetsURL)
+	}
+
+	check(c.Reload.CertInterval >= 0, "reload.cert_interval", "must not be negative")
+
+	check(c.RateLimit.RPS >= 0, "rate_limit.rps", "must not be negative")
+	check(c.RateLimit.RPS == 0 || c.RateLimit.Burst > 0, "rate_limit.burst", "must be positive when rate_limit.rps is set")
+
+	for i, origin := range c.CORS.AllowedOrigins {
+		field := fmt.Sprintf("cors.allowed_origins[%d]", i)
+		if origin == "*" {
+			check(!c.CORS.AllowCredentials, field, "must list explicit origins when cors.allow_credentials is set")
+			continue
+		}
+		check(validOrigin(origin), field, "must be scheme://host[:port] with at most one * in the host, got %q", origin)
+	}
+	for i, method := range c.CORS.AllowedMethods {
+		check(method != "" && method == strings.ToUpper(method), fmt.Sprintf("cors.allowed_methods[%d]", i), "must be an upper case HTTP method, got %q", method)
+	}
+	check(c.CORS.MaxAge >= 0, "cors.max_age", "must not be negative")
+
+	check(c.HTTPSecurity.HSTSMaxAge >= 0, "http_security.hsts_max_age", "must not be negative")
+	check(c.HTTPSecurity.FrameOptions == "" || c.HTTPSecurity.FrameOptions == "DENY" || c.HTTPSecurity.FrameOptions == "SAMEORIGIN",
+		"http_security.frame_options",
+		"must be DENY, SAMEORIGIN or empty, got %q", c.HTTPSecurity.FrameOptions)
+	check(c.HTTPSecurity.MaxBodySize >= 0, "http_security.max_body_size", "must not be negative")
+
+	tokens := make(map[string]struct{}, len(c.Auth.Tokens))
+	for i, t := range c.Auth.Tokens {
+		field := fmt.Sprintf("auth.tokens[%d]", i)
+		check(t.Token != "", field+".token", "is required")
+		check(t.Subject != "", field+".subject", "is required")
+		check(t.Role != "", field+".role", "is required")
+
+		_, duplicate := tokens[t.Token]
+		check(!duplicate, field+".token", "is duplicated")
+		tokens[t.Token] = struct{}{}
+	}
+	for i, r := range c.Auth.Rules {
+		field := fmt.Sprintf("auth.rules[%d]", i)
+		check(strings.HasPrefix(r.Method, "/"), field+".method", "must be a full gRPC method name, got %q", r.Method)
+		check(len(r.Roles) > 0, field+".roles", "must not be empty")
+	}
+
+	check(c.Reload.Interval >= 0, "reload.interval", "must not be negative")
+	check(c.Redaction.MaxSize > 0, "redaction.max_size", "must be positive")
+
+	check(c.Health.Timeout > 0, "health.timeout", "must be positive")
+	check(c.Health.CacheTTL >= 0, "health.cache_ttl", "must not be negative")
+	check(c.Health.Interval > 0, "health.interval", "must be positive")
+
+	check(c.Shutdown.Timeout > 0, "shutdown.timeout", "must be positive")
+	check(c.Shutdown.CloserTimeout > 0, "shutdown.closer_timeout", "must be positive")
+	check(c.Shutdown.DrainDelay >= 0, "shutdown.drain_delay", "must not be negative")
+	check(c.Shutdown.DrainDelay < c.Shutdown.Timeout, "shutdown.drain_delay", "must be less than shutdown.timeout")
+
+	check(c.Startup.Attempts > 0, "startup.attempts", "must be positive")
+	check(c.Startup.AttemptTimeout > 0, "startup.attempt_timeout", "must be positive")
+	check(c.Startup.Backoff >= 0, "startup.backoff", "must not be negative")
+	check(c.Startup.MaxBackoff >= c.Startup.Backoff, "startup.max_backoff", "must not be less than startup.backoff")
+
+	return errors.Join(problems...)
+}
+
+// Reloadable возвращает подмножество конфигурации, которое можно менять без перезапуска
+func (c *Config) Reloadable() Reloadable {
+	return Reloadable{
+		LogLevel:               c.Logger.Level,
+		LogSubsystems:          c.Logger.Subsystems,
+		RateLimit:              c.RateLimit,
+		SentrySampleRate:       c.Sentry.SampleRate,
+		SentryTracesSampleRate: c.Sentry.TracesSampleRate,
+		CORS:                   c.CORS,
+		Auth:                   c.Auth,
+	}
+}
+
+// WithReloadable возвращает копию конфигурации с подставленным перезагружаемым подмножеством
+func (c *Config) WithReloadable(r Reloadable) *Config {
+	next := *c
+	next.Logger.Level = r.LogLevel
+	next.Logger.Subsystems = r.LogSubsystems
+	next.RateLimit = r.RateLimit
+	next.Sentry.SampleRate = r.SentrySampleRate
+	next.Sentry.TracesSampleRate = r.SentryTracesSampleRate
+	next.CORS = r.CORS
+	next.Auth = r.Auth
+
+	return &next
+}
+
+// PGConfig возвращает настройки PostgreSQL
+func (c *Config) PGConfig() PGConfig {
+	return &pgConfig{dsn: c.PG.DSN}
+}
+
+// GRPCConfig возвращает настройки gRPC сервера
+func (c *Config) GRPCConfig() GRPCConfig {
+	return &grpcConfig{host: c.GRPC.Host, port: c.GRPC.Port}
+}
+
+// HTTPConfig возвращает настройки HTTP сервера
+func (c *Config) HTTPConfig() HTTPConfig {
+	return &httpConfig{host: c.HTTP.Host, port: c.HTTP.Port}
+}
+
+// SentryConfig возвращает настройки Sentry
+func (c *Config) SentryConfig() *SentryConfig {
+	sentryConfig := c.Sentry
+	return &sentryConfig
+}
+
+// Address возвращает адрес в формате host:port
+func (l Listener) Address() string {
+	return net.JoinHostPort(l.Host, l.Port)
+}
+
+func validPort(port string) bool {
+	p, err := strconv.Atoi(port)
+	return err == nil && p > 0 && p <= 65535
+}
+
+func validAddress(address string) bool {
+	_, port, err := net.SplitHostPort(address)
+	return err == nil && validPort(port)
+}
+
+// validOrigin проверяет источник CORS вида scheme://host[:port], * допускается один раз в хосте
+func validOrigin(origin string) bool {
+	if strings.Count(origin, "*") > 1 {
+		return false
+	}
+
+	u, err := url.Parse(strings.Replace(origin, "*", "wildcard", 1))
+	if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" || u.RawQuery != "" || u.User != nil {
+		return false
+	}
+
+	return !strings.Contains(origin, "*") || strings.Contains(u.Hostname(), "wildcard")
+}
+
+// validBaseURL проверяет абсолютный http(s) URL
+func validBaseURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func (t ServerTLS) validate(prefix string) []error {
+	if !t.Enabled {
+		return nil
+	}
+
+	var problems []error
+	check := func(ok bool, field, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Errorf("%s.%s: %s", prefix, field, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	check(t.CertFile != "", "cert_file", "is required when TLS is enabled")
+	check(t.KeyFile != "", "key_file", "is required when TLS is enabled")
+	switch t.ClientAuth {
+	case "", "none":
+	case "request", "require":
+		check(t.ClientCAFile != "", "client_ca_file", "is required when client_auth is %s", t.ClientAuth)
+	default:
+		check(false, "client_auth", "must be one of none, request, require, got %q", t.ClientAuth)
+	}
+	check(t.MinVersion == "" || t.MinVersion == "1.2" || t.MinVersion == "1.3",
+		"min_version", "must be 1.2 or 1.3, got %q", t.MinVersion)
+
+	return problems
+}
+
+func (t ClientTLS) validate(prefix string) []error {
+	if !t.Enabled || (t.CertFile == "") == (t.KeyFile == "") {
+		return nil
+	}
+
+	return []error{fmt.Errorf("%s: cert_file and key_file must be set together", prefix)}
+}
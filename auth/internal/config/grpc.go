@@ -2,14 +2,6 @@ package config
 
 import (
 	"net"
-	"os"
-
-	"github.com/pkg/errors"
-)
-
-const (
-	grpcHostEnvName = "GRPC_HOST"
-	grpcPortEnvName = "GRPC_PORT"
 )
 
 type GRPCConfig interface {
@@ -21,23 +13,6 @@ type grpcConfig struct {
 	port string
 }
 
-func NewGRPCConfig() (GRPCConfig, error) {
-	host := os.Getenv(grpcHostEnvName)
-	if len(host) == 0 {
-		return nil, errors.New("grpc host not found")
-	}
-
-	port := os.Getenv(grpcPortEnvName)
-	if len(port) == 0 {
-		return nil, errors.New("grpc port not found")
-	}
-
-	return &grpcConfig{
-		host: host,
-		port: port,
-	}, nil
-}
-
 func (cfg *grpcConfig) Address() string {
 	return net.JoinHostPort(cfg.host, cfg.port)
 }
@@ -2,14 +2,6 @@ package config
 
 import (
 	"net"
-	"os"
-
-	"github.com/pkg/errors"
-)
-
-const (
-	httpHostEnvName = "HTTP_HOST"
-	httpPortEnvName = "HTTP_PORT"
 )
 
 type HTTPConfig interface {
@@ -21,23 +13,6 @@ type httpConfig struct {
 	port string
 }
 
-func NewHTTPConfig() (HTTPConfig, error) {
-	host := os.Getenv(httpHostEnvName)
-	if len(host) == 0 {
-		return nil, errors.New("http host not found")
-	}
-
-	port := os.Getenv(httpPortEnvName)
-	if len(port) == 0 {
-		return nil, errors.New("http port not found")
-	}
-
-	return &httpConfig{
-		host: host,
-		port: port,
-	}, nil
-}
-
 func (cfg *httpConfig) Address() string {
 	return net.JoinHostPort(cfg.host, cfg.port)
 }
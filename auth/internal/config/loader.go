@@ -0,0 +1,317 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	configPathEnvName = "CONFIG_PATH"
+	defaultEnvFile    = ".env"
+	secretMask        = "******"
+)
+
+var dsnPasswordRegex = regexp.MustCompile(`(password=)(\S+)`)
+
+// Loader собирает Config из YAML файла, .env файла, переменных окружения и флагов.
+// Load можно вызывать повторно: файлы и окружение перечитываются, флаги сохраняются.
+type Loader struct {
+	configPath  string
+	envFile     string
+	printConfig bool
+	overrides   map[string]string
+}
+
+// NewLoader создает загрузчик и регистрирует флаги конфигурации в fs.
+// Если fs == nil, флаги не регистрируются.
+func NewLoader(fs *flag.FlagSet) *Loader {
+	l := &Loader{
+		configPath: os.Getenv(configPathEnvName),
+		envFile:    defaultEnvFile,
+		overrides:  make(map[string]string),
+	}
+
+	if fs == nil {
+		return l
+	}
+
+	fs.StringVar(&l.configPath, "config", l.configPath, "path to YAML config file (env "+configPathEnvName+")")
+	fs.StringVar(&l.envFile, "env-file", l.envFile, "path to .env file, ignored if missing")
+	fs.BoolVar(&l.printConfig, "print-config", false, "print effective configuration with secrets masked and exit")
+
+	walkFields(reflect.ValueOf(Default()).Elem(), "", "", func(f field) {
+		usage := f.usage
+		if f.env != "" {
+			usage += " (env " + f.env + ")"
+		}
+		if def := fmt.Sprint(f.value.Interface()); f.secret == "" && def != "" && !f.value.IsZero() {
+			usage += fmt.Sprintf(" (default %q)", def)
+		}
+
+		path := f.path
+		fs.Func(flagName(path), usage, func(s string) error {
+			l.overrides[path] = s
+			return nil
+		})
+	})
+
+	return l
+}
+
+// Files возвращает файлы, из которых читается конфигурация
+func (l *Loader) Files() []string {
+	var files []string
+	for _, file := range []string{l.configPath, l.envFile} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+// PrintConfig сообщает, передан ли флаг -print-config
+func (l *Loader) PrintConfig() bool {
+	return l.printConfig
+}
+
+// Load собирает и валидирует конфигурацию. Ошибка содержит все найденные проблемы.
+func (l *Loader) Load() (*Config, error) {
+	cfg := Default()
+
+	if l.configPath != "" {
+		if err := readYAML(l.configPath, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	dotenv := map[string]string{}
+	if l.envFile != "" {
+		values, err := godotenv.Read(l.envFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read env file %s: %w", l.envFile, err)
+		}
+		if err == nil {
+			dotenv = values
+		}
+	}
+
+	var problems []error
+	walkFields(reflect.ValueOf(cfg).Elem(), "", "", func(f field) {
+		if f.env != "" {
+			if value, ok := os.LookupEnv(f.env); ok {
+				if err := setFromString(f.value, value); err != nil {
+					problems = append(problems, fmt.Errorf("%s: invalid value of %s: %w", f.path, f.env, err))
+				}
+			} else if value, ok := dotenv[f.env]; ok {
+				if err := setFromString(f.value, value); err != nil {
+					problems = append(problems, fmt.Errorf("%s: invalid value of %s in %s: %w", f.path, f.env, l.envFile, err))
+				}
+			}
+		}
+
+		if value, ok := l.overrides[f.path]; ok {
+			if err := setFromString(f.value, value); err != nil {
+				problems = append(problems, fmt.Errorf("%s: invalid value of -%s: %w", f.path, flagName(f.path), err))
+			}
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		problems = append(problems, err)
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+
+	return cfg, nil
+}
+
+// MaskedYAML возвращает конфигурацию в YAML, заменяя секреты маской
+func (c *Config) MaskedYAML() ([]byte, error) {
+	// Копируем через YAML, чтобы не изменить срезы исходной конфигурации
+	raw, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	masked := &Config{}
+	if err = yaml.Unmarshal(raw, masked); err != nil {
+		return nil, err
+	}
+
+	walkFields(reflect.ValueOf(masked).Elem(), "", "", func(f field) {
+		if f.secret == "" || f.value.Kind() != reflect.String || f.value.String() == "" {
+			return
+		}
+
+		if f.secret == "dsn" {
+			f.value.SetString(maskDSN(f.value.String()))
+			return
+		}
+		f.value.SetString(secretMask)
+	})
+
+	return yaml.Marshal(masked)
+}
+
+func readYAML(path string, cfg *Config) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// field лист конфигурации, который можно задать одной строкой
+type field struct {
+	path   string
+	env    string
+	usage  string
+	secret string
+	value  reflect.Value
+}
+
+// walkFields обходит листья структуры конфигурации. Путь строится из тегов yaml,
+// имя переменной окружения — из тега env с префиксом из тега env родительской структуры.
+func walkFields(v reflect.Value, path, envPrefix string, fn func(field)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := strings.Split(sf.Tag.Get("yaml"), ",")[0]
+		if name == "-" || !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+
+		env := sf.Tag.Get("env")
+		if env != "" {
+			env = envPrefix + env
+		}
+
+		fv := v.Field(i)
+		switch {
+		case fv.Kind() == reflect.Struct:
+			walkFields(fv, fieldPath, env, fn)
+		case fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Struct:
+			for j := 0; j < fv.Len(); j++ {
+				walkFields(fv.Index(j), fmt.Sprintf("%s[%d]", fieldPath, j), "", fn)
+			}
+		default:
+			fn(field{
+				path:   fieldPath,
+				env:    env,
+				usage:  sf.Tag.Get("usage"),
+				secret: sf.Tag.Get("secret"),
+				value:  fv,
+			})
+		}
+	}
+}
+
+// setFromString устанавливает значение листа конфигурации из строки
+func setFromString(v reflect.Value, s string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		items := reflect.Zero(v.Type())
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if elem.Kind() == reflect.Slice || elem.Kind() == reflect.Struct {
+				return fmt.Errorf("unsupported type %s", v.Type())
+			}
+			if err := setFromString(elem, item); err != nil {
+				return err
+			}
+			items = reflect.Append(items, elem)
+		}
+		v.Set(items)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+func flagName(path string) string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(path)
+}
+
+// maskDSN скрывает пароль в DSN формата URL или key=value
+func maskDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			// url.UserPassword экранирует маску, поэтому заменяем userinfo вручную
+			return strings.Replace(dsn, u.User.String()+"@", u.User.Username()+":"+secretMask+"@", 1)
+		}
+		return dsn
+	}
+
+	return dsnPasswordRegex.ReplaceAllString(dsn, "${1}"+secretMask)
+}
@@ -1,14 +1,5 @@
 package config
 
-import (
-	"errors"
-	"os"
-)
-
-const (
-	dsnEnvName = "PG_DSN"
-)
-
 type PGConfig interface {
 	DSN() string
 }
@@ -17,17 +8,6 @@ type pgConfig struct {
 	dsn string
 }
 
-func NewPGConfig() (PGConfig, error) {
-	dsn := os.Getenv(dsnEnvName)
-	if len(dsn) == 0 {
-		return nil, errors.New("pg dsn not found")
-	}
-
-	return &pgConfig{
-		dsn: dsn,
-	}, nil
-}
-
 func (cfg *pgConfig) DSN() string {
 	return cfg.dsn
 }
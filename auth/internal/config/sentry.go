@@ -1,36 +1,64 @@
 package config
 
 import (
-	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
 )
 
 type SentryConfig struct {
-	DSN              string
-	Environment      string
-	Release          string
-	Debug            bool
-	SampleRate       float64
-	TracesSampleRate float64
+	DSN              string  `yaml:"dsn" env:"SENTRY_DSN" secret:"true" usage:"Sentry DSN, empty disables Sentry"`
+	Environment      string  `yaml:"environment" env:"SENTRY_ENVIRONMENT" usage:"Sentry environment"`
+	Release          string  `yaml:"release" env:"SENTRY_RELEASE" usage:"Sentry release"`
+	Debug            bool    `yaml:"debug" env:"SENTRY_DEBUG" usage:"enable Sentry SDK debug output"`
+	SampleRate       float64 `yaml:"sample_rate" env:"SENTRY_SAMPLE_RATE" usage:"share of error events sent to Sentry"`
+	TracesSampleRate float64 `yaml:"traces_sample_rate" env:"SENTRY_TRACES_SAMPLE_RATE" usage:"share of transactions sent to Sentry"`
+
+	// Фильтрация событий перед отправкой (BeforeSend)
+	ScrubFields     []string      `yaml:"scrub_fields" env:"SENTRY_SCRUB_FIELDS" usage:"comma separated key substrings whose values are removed from events: password,token"`
+	ScrubPatterns   []string      `yaml:"scrub_patterns" env:"SENTRY_SCRUB_PATTERNS" usage:"comma separated regular expressions removed from event texts in addition to emails and tokens"`
+	DedupWindow     time.Duration `yaml:"dedup_window" env:"SENTRY_DEDUP_WINDOW" usage:"window in which events with the same fingerprint are counted, 0 disables the limit"`
+	DedupLimit      int           `yaml:"dedup_limit" env:"SENTRY_DEDUP_LIMIT" usage:"events with the same fingerprint sent per dedup window"`
+	IgnoreGRPCCodes []string      `yaml:"ignore_grpc_codes" env:"SENTRY_IGNORE_GRPC_CODES" usage:"comma separated gRPC codes of errors not sent to Sentry: DeadlineExceeded,Canceled"`
+
+	// Параметры событий и SDK. 0 в max_breadcrumbs и flush_timeout — значение профиля окружения
+	ServerName     string        `yaml:"server_name" env:"SENTRY_SERVER_NAME" usage:"server name in events, empty uses the hostname"`
+	Dist           string        `yaml:"dist" env:"SENTRY_DIST" usage:"distribution of the release"`
+	Service        string        `yaml:"service" env:"SENTRY_SERVICE" usage:"value of the service tag"`
+	AdditionalTags []string      `yaml:"additional_tags" env:"SENTRY_ADDITIONAL_TAGS" usage:"comma separated key:value tags added to all events: team:auth,region:eu"`
+	MaxBreadcrumbs int           `yaml:"max_breadcrumbs" env:"SENTRY_MAX_BREADCRUMBS" usage:"breadcrumbs kept per event, 0 uses the environment profile"`
+	MaxSpans       int           `yaml:"max_spans" env:"SENTRY_MAX_SPANS" usage:"spans kept per transaction"`
+	FlushTimeout   time.Duration `yaml:"flush_timeout" env:"SENTRY_FLUSH_TIMEOUT" usage:"time to send queued events on shutdown, 0 uses the environment profile"`
+
+	SlowThreshold SentrySlowThreshold `yaml:"slow_threshold" env:"SENTRY_SLOW"`
 }
 
-func NewSentryConfig() *SentryConfig {
-	return &SentryConfig{
-		DSN:              getEnv("SENTRY_DSN", ""),
-		Environment:      getEnv("SENTRY_ENVIRONMENT", "development"),
-		Release:          getEnv("SENTRY_RELEASE", ""),
-		Debug:            getEnv("SENTRY_DEBUG", "false") == "true",
-		SampleRate:       1.0,
-		TracesSampleRate: 0.1,
-	}
+// SentrySlowThreshold пороги, после которых операция отправляется в Reporter как проблема
+// производительности, 0 отключает порог. Действуют для любого backend из error_reporting.
+type SentrySlowThreshold struct {
+	Request time.Duration `yaml:"request" env:"_REQUEST_THRESHOLD" usage:"unary gRPC call duration reported as slow"`
+	Stream  time.Duration `yaml:"stream" env:"_STREAM_THRESHOLD" usage:"gRPC stream and business operation duration reported as slow"`
+	Query   time.Duration `yaml:"query" env:"_QUERY_THRESHOLD" usage:"database query duration reported as slow"`
 }
 
 func (c *SentryConfig) IsEnabled() bool {
 	return c.DSN != ""
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// GRPCCode возвращает код gRPC по имени в виде codes.Code.String(), например NotFound
+func GRPCCode(name string) (codes.Code, bool) {
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		if code.String() == name {
+			return code, true
+		}
 	}
-	return defaultValue
+	return 0, false
+}
+
+// SentryTag разбирает тег из additional_tags в формате key:value
+func SentryTag(raw string) (key, value string, ok bool) {
+	key, value, ok = strings.Cut(raw, ":")
+	key = strings.TrimSpace(key)
+	return key, strings.TrimSpace(value), ok && key != ""
 }
@@ -0,0 +1,23 @@
+package converter
+
+import (
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	desc "github.com/MercerMorning/go_example/auth/pkg/admin_v1"
+)
+
+// ToLogLevelResponseFromLevels конвертирует уровни логирования в LogLevelResponse
+func ToLogLevelResponseFromLevels(level string, subsystems map[string]logger.SubsystemLevel) *desc.LogLevelResponse {
+	resp := &desc.LogLevelResponse{
+		Level:      level,
+		Subsystems: make(map[string]*desc.SubsystemLevel, len(subsystems)),
+	}
+
+	for name, s := range subsystems {
+		resp.Subsystems[name] = &desc.SubsystemLevel{
+			Level:     s.Level,
+			Inherited: s.Inherited,
+		}
+	}
+
+	return resp
+}
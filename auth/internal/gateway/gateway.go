@@ -0,0 +1,144 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+	desc "github.com/MercerMorning/go_example/auth/pkg/user_v1"
+)
+
+// forwardedHeaders HTTP заголовки, которые передаются в gRPC метаданные без префикса grpcgateway-
+var forwardedHeaders = map[string]string{
+	"x-request-id":    requestid.MetadataKey,
+	"idempotency-key": "idempotency-key",
+}
+
+// createdMethods методы, создающие ресурс: на них шлюз отвечает 201 Created
+var createdMethods = map[string]bool{
+	desc.UserV1_Create_FullMethodName: true,
+}
+
+// NewServeMux создает mux шлюза: JSON с именами полей из proto и нулевыми значениями,
+// проброс заголовков авторизации, request ID и ключа идемпотентности, ошибки в едином
+// формате и коды ответа 201/204. opts дополняют эти настройки.
+func NewServeMux(opts ...runtime.ServeMuxOption) *runtime.ServeMux {
+	return runtime.NewServeMux(append([]runtime.ServeMuxOption{
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.HTTPBodyMarshaler{
+			Marshaler: &runtime.JSONPb{
+				MarshalOptions: protojson.MarshalOptions{
+					UseProtoNames:   true,
+					EmitUnpopulated: true,
+				},
+				UnmarshalOptions: protojson.UnmarshalOptions{
+					DiscardUnknown: true,
+				},
+			},
+		}),
+		runtime.WithIncomingHeaderMatcher(HeaderMatcher),
+		runtime.WithErrorHandler(ErrorHandler),
+		runtime.WithRoutingErrorHandler(RoutingErrorHandler),
+		runtime.WithForwardResponseOption(ResponseStatus),
+	}, opts...)...)
+}
+
+// HeaderMatcher передает в gRPC заголовки X-Request-ID и Idempotency-Key, остальные —
+// по правилам шлюза по умолчанию. Authorization шлюз сам передает как authorization,
+// поэтому копия grpcgateway-authorization с токеном не создается.
+func HeaderMatcher(key string) (string, bool) {
+	key = strings.ToLower(key)
+	if key == "authorization" {
+		return "", false
+	}
+	if name, ok := forwardedHeaders[key]; ok {
+		return name, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// ResponseStatus отвечает 201 Created на создание ресурса и 204 No Content на пустой ответ
+func ResponseStatus(ctx context.Context, w http.ResponseWriter, resp proto.Message) error {
+	if _, ok := resp.(*emptypb.Empty); ok {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+
+	if method, ok := runtime.RPCMethod(ctx); ok && createdMethods[method] {
+		w.WriteHeader(http.StatusCreated)
+	}
+
+	return nil
+}
+
+// errorBody тело ответа с ошибкой
+type errorBody struct {
+	Code      codes.Code `json:"code"`
+	Status    string     `json:"status"`
+	Message   string     `json:"message"`
+	RequestID string     `json:"request_id,omitempty"`
+}
+
+// ErrorHandler отвечает на ошибку gRPC вызова или маршрутизации HTTP статусом,
+// соответствующим коду gRPC, и телом с кодом, сообщением и request ID.
+// Тело больше лимита MaxBodySize дает 413.
+func ErrorHandler(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	if state, ok := bodyTooLarge(r); ok {
+		writeBodyTooLarge(w, r, state)
+		return
+	}
+
+	WriteError(w, r, err)
+}
+
+// RoutingErrorHandler отвечает в формате ErrorHandler, когда шлюз не нашел маршрут,
+// сохраняя HTTP статус: 404 или 405
+func RoutingErrorHandler(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, httpStatus int) {
+	c := codes.Internal
+	switch httpStatus {
+	case http.StatusNotFound:
+		c = codes.NotFound
+	case http.StatusMethodNotAllowed:
+		c = codes.Unimplemented
+	case http.StatusBadRequest:
+		c = codes.InvalidArgument
+	}
+
+	writeError(w, r, status.New(c, http.StatusText(httpStatus)), httpStatus)
+}
+
+// WriteError пишет ошибку в формате ErrorHandler. Используется и вне шлюза,
+// чтобы все HTTP ошибки сервиса выглядели одинаково.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	st := status.Convert(err)
+	writeError(w, r, st, runtime.HTTPStatusFromCode(st.Code()))
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, st *status.Status, httpStatus int) {
+	id, _ := requestid.FromContext(r.Context())
+
+	if st.Code() == codes.Unauthenticated {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+
+	w.Header().Del("Trailer")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+
+	_ = json.NewEncoder(w).Encode(errorBody{
+		Code:      st.Code(),
+		Status:    code.Code(st.Code()).String(),
+		Message:   st.Message(),
+		RequestID: id,
+	})
+}
@@ -0,0 +1,72 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type bodyLimitKey struct{}
+
+// bodyLimit отмечает, что тело запроса оказалось больше лимита
+type bodyLimit struct {
+	limit    int64
+	exceeded atomic.Bool
+}
+
+// limitedBody запоминает ошибку http.MaxBytesReader, чтобы ErrorHandler ответил 413,
+// а не 400, с которым шлюз возвращает ошибки чтения тела
+type limitedBody struct {
+	io.ReadCloser
+	state *bodyLimit
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		b.state.exceeded.Store(true)
+	}
+
+	return n, err
+}
+
+// MaxBodySize ограничивает тело запроса limit байтами. При большем Content-Length отвечает
+// 413 сразу, тело без длины прерывается при чтении, и ошибка шлюза тоже становится 413.
+// limit = 0 отключает ограничение.
+func MaxBodySize(next http.Handler, limit int64) http.Handler {
+	if limit <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state := &bodyLimit{limit: limit}
+		if r.ContentLength > limit {
+			writeBodyTooLarge(w, r, state)
+			return
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), bodyLimitKey{}, state))
+		r.Body = &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, limit), state: state}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bodyTooLarge возвращает лимит, если тело запроса его превысило
+func bodyTooLarge(r *http.Request) (*bodyLimit, bool) {
+	state, ok := r.Context().Value(bodyLimitKey{}).(*bodyLimit)
+	return state, ok && state.exceeded.Load()
+}
+
+func writeBodyTooLarge(w http.ResponseWriter, r *http.Request, state *bodyLimit) {
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("request body exceeds %d bytes", state.limit))
+	writeError(w, r, st, http.StatusRequestEntityTooLarge)
+}
@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/MercerMorning/go_example/auth/internal/gateway"
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+	desc "github.com/MercerMorning/go_example/auth/pkg/user_v1"
+)
+
+type userServer struct {
+	desc.UnimplementedUserV1Server
+	md     chan metadata.MD
+	update chan *desc.UpdateRequest
+}
+
+func (s *userServer) Create(ctx context.Context, _ *desc.CreateRequest) (*desc.CreateResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.md <- md
+	return &desc.CreateResponse{Id: 1}, nil
+}
+
+func (s *userServer) Get(_ context.Context, req *desc.GetRequest) (*desc.GetResponse, error) {
+	if req.GetId() != 7 {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return &desc.GetResponse{Id: 7, Email: "alice@example.com"}, nil
+}
+
+func (s *userServer) Update(_ context.Context, req *desc.UpdateRequest) (*emptypb.Empty, error) {
+	s.update <- req
+	return &emptypb.Empty{}, nil
+}
+
+func (s *userServer) Delete(context.Context, *desc.DeleteRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func TestGatewayHTTPSemantics(t *testing.T) {
+	srv := &userServer{md: make(chan metadata.MD, 1), update: make(chan *desc.UpdateRequest, 1)}
+	handler := newGateway(t, srv, 0)
+
+	// POST создает ресурс и передает заголовки в метаданные без префикса
+	rec := do(t, handler, http.MethodPost, "/user/v1/create", `{"name":"alice"}`, map[string]string{
+		"Authorization":   "Bearer secret",
+		"Idempotency-Key": "key-1",
+		"X-Request-ID":    "req-1",
+	})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.JSONEq(t, `{"id":"1"}`, rec.Body)
+	md := <-srv.md
+	require.Equal(t, []string{"Bearer secret"}, md.Get("authorization"))
+	require.Equal(t, []string{"key-1"}, md.Get("idempotency-key"))
+	require.Equal(t, []string{"req-1"}, md.Get(requestid.MetadataKey))
+
+	// Имена полей из proto, нулевые значения не пропускаются
+	rec = do(t, handler, http.MethodGet, "/user/v1/7", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var user map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(rec.Body), &user))
+	require.Equal(t, "alice@example.com", user["email"])
+	require.Equal(t, "", user["name"])
+	require.Equal(t, "USER", user["role"])
+	require.Contains(t, user, "created_at")
+
+	rec = do(t, handler, http.MethodPatch, "/user/v1/7", `{"name":"bob"}`, nil)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Empty(t, rec.Body)
+	update := <-srv.update
+	require.EqualValues(t, 7, update.GetId())
+	require.Equal(t, "bob", update.GetName().GetValue())
+	require.Nil(t, update.GetEmail())
+
+	rec = do(t, handler, http.MethodDelete, "/user/v1/7", "", nil)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestGatewayErrors(t *testing.T) {
+	handler := newGateway(t, &userServer{}, 0)
+
+	rec := do(t, handler, http.MethodGet, "/user/v1/8", "", map[string]string{"X-Request-ID": "req-2"})
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.JSONEq(t, `{"code":5,"status":"NOT_FOUND","message":"user not found","request_id":"req-2"}`, rec.Body)
+
+	rec = do(t, handler, http.MethodPut, "/user/v1/8", "", map[string]string{"X-Request-ID": "req-3"})
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	require.Contains(t, rec.Body, `"request_id":"req-3"`)
+}
+
+func TestGatewayMaxBodySize(t *testing.T) {
+	srv := &userServer{md: make(chan metadata.MD, 1)}
+	handler := newGateway(t, srv, 32)
+
+	rec := do(t, handler, http.MethodPost, "/user/v1/create", `{"name":"alice"}`, nil)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	<-srv.md
+
+	body := `{"name":"` + strings.Repeat("a", 64) + `"}`
+	rec = do(t, handler, http.MethodPost, "/user/v1/create", body, nil)
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	require.Contains(t, rec.Body, "request body exceeds 32 bytes")
+
+	// Без Content-Length тело обрывается при чтении
+	req, err := http.NewRequest(http.MethodPost, handler.URL+"/user/v1/create", io.MultiReader(strings.NewReader(body)))
+	require.NoError(t, err)
+	resp, err := handler.Client().Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func newGateway(t *testing.T, srv desc.UserV1Server, maxBodySize int64) *httptest.Server {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	desc.RegisterUserV1Server(server, srv)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	mux := gateway.NewServeMux()
+	require.NoError(t, desc.RegisterUserV1HandlerClient(context.Background(), mux, desc.NewUserV1Client(conn)))
+
+	httpServer := httptest.NewServer(requestid.HTTPMiddleware(gateway.MaxBodySize(mux, maxBodySize)))
+	t.Cleanup(httpServer.Close)
+
+	return httpServer
+}
+
+type response struct {
+	Code int
+	Body string
+}
+
+func do(t *testing.T, server *httptest.Server, method, path, body string, headers map[string]string) response {
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return response{Code: resp.StatusCode, Body: string(raw)}
+}
@@ -0,0 +1,40 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/client/db"
+)
+
+// DB проверяет соединение с БД
+func DB(p db.Pinger) Checker {
+	return p.Ping
+}
+
+// GRPC проверяет исходящее gRPC соединение через grpc.health.v1.
+// Если сервис не реализует grpc.health.v1, достаточно того, что он ответил.
+func GRPC(conn grpc.ClientConnInterface) Checker {
+	client := healthpb.NewHealthClient(conn)
+
+	return func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if status.Code(err) == codes.Unimplemented {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("status %s", resp.GetStatus())
+		}
+
+		return nil
+	}
+}
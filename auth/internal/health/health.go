@@ -0,0 +1,250 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	grpcHealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+)
+
+// ErrDraining возвращается проверкой готовности, пока сервис завершает работу
+var ErrDraining = errors.New("service is shutting down")
+
+// Checker проверяет доступность зависимости
+type Checker func(ctx context.Context) error
+
+// Result результат проверки одной зависимости
+type Result struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// Report результат проверки готовности
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks,omitempty"`
+}
+
+// Ready сообщает, готов ли сервис принимать запросы
+func (r Report) Ready() bool {
+	return r.Status == statusOK
+}
+
+const (
+	statusOK   = "ok"
+	statusFail = "fail"
+)
+
+type check struct {
+	name     string
+	checker  Checker
+	optional bool
+
+	mu        sync.Mutex
+	err       error
+	checkedAt time.Time
+}
+
+// Registry реестр проверок готовности. Подсистемы регистрируют в нем свои проверки,
+// а результат отдается через /readyz и сервис grpc.health.v1.
+type Registry struct {
+	cfg      config.Health
+	draining atomic.Bool
+	server   *grpcHealth.Server
+
+	mu       sync.RWMutex
+	checks   []*check
+	services []string
+}
+
+// New создает реестр проверок
+func New(cfg config.Health) *Registry {
+	return &Registry{
+		cfg:    cfg,
+		server: grpcHealth.NewServer(),
+	}
+}
+
+// Register добавляет проверку, от которой зависит готовность сервиса
+func (r *Registry) Register(name string, checker Checker) {
+	r.add(&check{name: name, checker: checker})
+}
+
+// RegisterOptional добавляет проверку, которая отображается в отчете, но не влияет на готовность
+func (r *Registry) RegisterOptional(name string, checker Checker) {
+	r.add(&check{name: name, checker: checker, optional: true})
+}
+
+func (r *Registry) add(c *check) {
+	r.mu.Lock()
+	r.checks = append(r.checks, c)
+	r.mu.Unlock()
+}
+
+// RegisterGRPC регистрирует сервис grpc.health.v1 на сервере. Статус отдается
+// для всего сервера ("") и для каждого зарегистрированного на нем сервиса.
+func (r *Registry) RegisterGRPC(s *grpc.Server) {
+	healthpb.RegisterHealthServer(s, r.server)
+
+	r.mu.Lock()
+	r.services = []string{""}
+	for name := range s.GetServiceInfo() {
+		r.services = append(r.services, name)
+	}
+	r.mu.Unlock()
+}
+
+// Check выполняет проверки. Результаты моложе cache_ttl берутся из кэша,
+// каждая проверка ограничена timeout.
+func (r *Registry) Check(ctx context.Context) Report {
+	if r.draining.Load() {
+		return Report{Status: statusFail, Checks: []Result{{Name: "drain", Status: statusFail, Error: ErrDraining.Error()}}}
+	}
+
+	r.mu.RLock()
+	checks := r.checks
+	r.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			results[i] = r.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: statusOK, Checks: results}
+	for _, res := range results {
+		if res.Status != statusOK && !res.Optional {
+			report.Status = statusFail
+		}
+	}
+
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, c *check) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.checkedAt.IsZero() || time.Since(c.checkedAt) >= r.cfg.CacheTTL {
+		c.err = r.call(ctx, c)
+		c.checkedAt = time.Now()
+
+		if c.err != nil {
+			logger.Warn("Health check failed", zap.String("check", c.name), zap.Error(c.err))
+		}
+	}
+
+	res := Result{Name: c.name, Status: statusOK, Optional: c.optional}
+	if c.err != nil {
+		res.Status = statusFail
+		res.Error = c.err.Error()
+	}
+
+	return res
+}
+
+func (r *Registry) call(ctx context.Context, c *check) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+	defer cancel()
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("check panicked: %v", p)
+		}
+	}()
+
+	return c.checker(ctx)
+}
+
+// Run периодически обновляет статус сервиса grpc.health.v1. Блокируется до отмены ctx.
+func (r *Registry) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.updateGRPCStatus(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Registry) updateGRPCStatus(ctx context.Context) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if !r.Check(ctx).Ready() {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	r.mu.RLock()
+	services := r.services
+	r.mu.RUnlock()
+
+	for _, name := range services {
+		r.server.SetServingStatus(name, status)
+	}
+}
+
+// Drain переводит сервис в NOT_SERVING перед завершением, чтобы балансировщик
+// перестал направлять новые запросы. Статус больше не меняется.
+func (r *Registry) Drain() {
+	if r.draining.Swap(true) {
+		return
+	}
+
+	logger.Info("Health status switched to NOT_SERVING, draining")
+	r.server.Shutdown()
+}
+
+// LivenessHandler отвечает 200, пока процесс способен обрабатывать HTTP запросы
+func (r *Registry) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeReport(w, http.StatusOK, Report{Status: statusOK})
+	})
+}
+
+// ReadinessHandler отвечает 200, если все обязательные проверки прошли, иначе 503
+func (r *Registry) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := r.Check(req.Context())
+
+		code := http.StatusOK
+		if !report.Ready() {
+			code = http.StatusServiceUnavailable
+		}
+		writeReport(w, code, report)
+	})
+}
+
+func writeReport(w http.ResponseWriter, code int, report Report) {
+	sort.SliceStable(report.Checks, func(i, j int) bool {
+		return report.Checks[i].Name < report.Checks[j].Name
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report)
+}
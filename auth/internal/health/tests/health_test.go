@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/health"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(zapcore.NewNopCore())
+	os.Exit(m.Run())
+}
+
+func TestReadinessCachesAndTimesOut(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	registry := health.New(config.Health{Timeout: 50 * time.Millisecond, CacheTTL: time.Minute, Interval: time.Second})
+	registry.Register("postgres", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+	registry.RegisterOptional("other_service", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report := readyz(t, registry, http.StatusOK)
+	require.Len(t, report.Checks, 2)
+	require.Equal(t, "other_service", report.Checks[0].Name)
+	require.Equal(t, "fail", report.Checks[0].Status)
+	require.Contains(t, report.Checks[0].Error, "deadline exceeded")
+
+	readyz(t, registry, http.StatusOK)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestReadinessFailsOnRequiredCheck(t *testing.T) {
+	t.Parallel()
+
+	registry := health.New(config.Health{Timeout: time.Second, Interval: time.Second})
+	registry.Register("postgres", func(context.Context) error {
+		return errors.New("connection refused")
+	})
+
+	report := readyz(t, registry, http.StatusServiceUnavailable)
+	require.Equal(t, "connection refused", report.Checks[0].Error)
+
+	rec := httptest.NewRecorder()
+	registry.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestGRPCHealthAndDrain(t *testing.T) {
+	t.Parallel()
+
+	registry := health.New(config.Health{Timeout: time.Second, Interval: time.Second})
+	registry.Register("postgres", func(context.Context) error { return nil })
+
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	registry.RegisterGRPC(server)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	// Отмененный контекст: Run обновляет статус один раз и возвращается
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	registry.Run(ctx)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	require.NoError(t, health.GRPC(conn)(context.Background()))
+
+	registry.Drain()
+	require.ErrorContains(t, health.GRPC(conn)(context.Background()), "NOT_SERVING")
+	readyz(t, registry, http.StatusServiceUnavailable)
+}
+
+func readyz(t *testing.T, registry *health.Registry, code int) health.Report {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	registry.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.Equal(t, code, rec.Code)
+
+	var report health.Report
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+
+	return report
+}
@@ -0,0 +1,20 @@
+package httpstatus
+
+import "net/http"
+
+// Recorder запоминает код ответа, отправленный обработчиком. Пока обработчик не вызвал
+// WriteHeader, код — 200: его отправит первый Write.
+type Recorder struct {
+	http.ResponseWriter
+	Status int
+}
+
+// NewRecorder оборачивает w для middleware, которым нужен код ответа
+func NewRecorder(w http.ResponseWriter) *Recorder {
+	return &Recorder{ResponseWriter: w, Status: http.StatusOK}
+}
+
+func (r *Recorder) WriteHeader(status int) {
+	r.Status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,27 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MercerMorning/go_example/auth/internal/httpstatus"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	rec := httpstatus.NewRecorder(w)
+	_, err := rec.Write([]byte("ok"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, rec.Status)
+
+	w = httptest.NewRecorder()
+	rec = httpstatus.NewRecorder(w)
+	rec.WriteHeader(http.StatusNotFound)
+	require.Equal(t, http.StatusNotFound, rec.Status)
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
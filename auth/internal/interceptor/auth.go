@@ -0,0 +1,156 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+)
+
+const authorizationHeader = "authorization"
+
+type principalKey struct{}
+
+// Principal субъект, предъявивший токен доступа
+type Principal struct {
+	Subject string
+	Role    string
+}
+
+// PrincipalFromContext возвращает субъекта запроса, если он был аутентифицирован
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// ContextWithPrincipal возвращает контекст с субъектом запроса
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+type authPolicy struct {
+	tokens map[string]Principal
+	rules  []config.Rule
+}
+
+// Authorizer проверяет bearer токен и роль для методов, на которые заданы правила.
+// Токены и правила можно менять во время работы через Reload.
+type Authorizer struct {
+	policy atomic.Pointer[authPolicy]
+}
+
+// NewAuthorizer создает авторизатор с заданными токенами и правилами
+func NewAuthorizer(cfg config.Auth) *Authorizer {
+	a := &Authorizer{}
+	a.policy.Store(newAuthPolicy(cfg))
+
+	return a
+}
+
+// Reload атомарно заменяет токены и правила авторизации
+func (a *Authorizer) Reload(cfg config.Reloadable) error {
+	a.policy.Store(newAuthPolicy(cfg.Auth))
+	return nil
+}
+
+// Authenticate возвращает субъекта по значению заголовка Authorization
+func (a *Authorizer) Authenticate(authorization string) (Principal, bool) {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return Principal{}, false
+	}
+
+	p, ok := a.policy.Load().tokens[strings.TrimSpace(token)]
+	return p, ok
+}
+
+// Allowed сообщает, может ли роль вызывать метод. Методы без правил доступны всем.
+func (a *Authorizer) Allowed(method, role string) (allowed, restricted bool) {
+	for _, rule := range a.policy.Load().rules {
+		if !matchMethod(rule.Method, method) {
+			continue
+		}
+
+		restricted = true
+		for _, r := range rule.Roles {
+			if r == role {
+				return true, true
+			}
+		}
+	}
+
+	return !restricted, restricted
+}
+
+// Unary проверяет доступ к методу и кладет субъекта в контекст
+func (a *Authorizer) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// Stream проверяет доступ к методу при открытии потока и кладет субъекта в контекст потока
+func (a *Authorizer) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, withContext(ss, ctx))
+}
+
+func (a *Authorizer) authorize(ctx context.Context, method string) (context.Context, error) {
+	var authorization string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authorizationHeader); len(values) > 0 {
+			authorization = values[0]
+		}
+	}
+
+	p, authenticated := a.Authenticate(authorization)
+	if authenticated {
+		ctx = ContextWithPrincipal(ctx, p)
+		ctx = logger.AddFields(ctx, zap.String(logger.UserIDKey, p.Subject))
+	}
+
+	allowed, restricted := a.Allowed(method, p.Role)
+	switch {
+	case restricted && !authenticated:
+		return nil, status.Error(codes.Unauthenticated, "valid bearer token is required")
+	case !allowed:
+		return nil, status.Errorf(codes.PermissionDenied, "role %q is not allowed to call %s", p.Role, method)
+	}
+
+	return ctx, nil
+}
+
+func newAuthPolicy(cfg config.Auth) *authPolicy {
+	policy := &authPolicy{
+		tokens: make(map[string]Principal, len(cfg.Tokens)),
+		rules:  cfg.Rules,
+	}
+	for _, t := range cfg.Tokens {
+		policy.tokens[t.Token] = Principal{Subject: t.Subject, Role: t.Role}
+	}
+
+	return policy
+}
+
+func matchMethod(pattern, method string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(method, prefix)
+	}
+
+	return pattern == method
+}
@@ -5,20 +5,122 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/redact"
 )
 
+// LogInterceptor привязывает к контексту метод запроса и пишет запрос в лог одной записью,
+// при ошибке — с уровнем по коду gRPC.
+// Должен стоять после ServerTracingInterceptor и RequestIDInterceptor, чтобы в логах были
+// trace ID, span ID и request ID.
 func LogInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 	now := time.Now()
 
+	ctx = logger.ToContext(ctx, zap.String(logger.MethodKey, info.FullMethod))
+
 	res, err := handler(ctx, req)
-	if err != nil {
-		logger.Error(err.Error(), zap.String("method", info.FullMethod), zap.Any("req", req))
-	}
 
-	logger.Info("request", zap.String("method", info.FullMethod), zap.Any("req", req), zap.Any("res", res), zap.Duration("duration", time.Since(now)))
+	// Запрос и ответ маскируются, только если запись пройдет уровень подсистемы
+	level, fields := resultLevel(err)
+	if ce := logger.NamedFromContext(ctx, logger.SubsystemGRPC).Check(level, "request"); ce != nil {
+		ce.Write(append(fields,
+			zap.String("req", redact.String(req)),
+			zap.String("res", redact.String(res)),
+			zap.Duration("duration", time.Since(now)),
+		)...)
+	}
 
 	return res, err
 }
+
+// StreamLogInterceptor привязывает к контексту потока метод и пишет в лог каждое
+// сообщение (на уровне debug) и завершение потока с числом сообщений.
+// Стоит в той же позиции цепочки, что и LogInterceptor.
+func StreamLogInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	now := time.Now()
+
+	ctx := logger.ToContext(ss.Context(), zap.String(logger.MethodKey, info.FullMethod))
+	stream := &logServerStream{ServerStream: withContext(ss, ctx)}
+
+	err := handler(srv, stream)
+
+	level, fields := resultLevel(err)
+	if ce := logger.NamedFromContext(ctx, logger.SubsystemGRPC).Check(level, "stream"); ce != nil {
+		ce.Write(append(fields,
+			zap.Int("received", stream.received),
+			zap.Int("sent", stream.sent),
+			zap.Duration("duration", time.Since(now)),
+		)...)
+	}
+
+	return err
+}
+
+// resultLevel возвращает уровень записи о вызове и поля ошибки. Internal и Unknown уже отправляет
+// в Reporter интерцептор monitoring, поэтому запись помечается Reported. Ошибки клиента
+// пишутся предупреждением и не попадают в Sentry, остальные коды — ошибкой.
+func resultLevel(err error) (zapcore.Level, []zap.Field) {
+	if err == nil {
+		return zapcore.InfoLevel, nil
+	}
+
+	code := status.Code(err)
+	fields := []zap.Field{zap.Error(err), zap.String("grpc_code", code.String())}
+
+	switch code {
+	case codes.Internal, codes.Unknown:
+		return zapcore.ErrorLevel, append(fields, logger.Reported())
+	case codes.Canceled, codes.InvalidArgument, codes.DeadlineExceeded, codes.NotFound,
+		codes.AlreadyExists, codes.PermissionDenied, codes.ResourceExhausted, codes.FailedPrecondition,
+		codes.Aborted, codes.OutOfRange, codes.Unimplemented, codes.Unauthenticated:
+		return zapcore.WarnLevel, fields
+	default:
+		return zapcore.ErrorLevel, fields
+	}
+}
+
+// logServerStream пишет в лог сообщения потока. Send и Recv вызываются
+// из разных горутин, поэтому у каждого направления свой счетчик.
+type logServerStream struct {
+	grpc.ServerStream
+	received int
+	sent     int
+}
+
+func (s *logServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+		s.log("send", s.sent, m)
+	}
+
+	return err
+}
+
+func (s *logServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received++
+		s.log("recv", s.received, m)
+	}
+
+	return err
+}
+
+func (s *logServerStream) log(direction string, seq int, m interface{}) {
+	grpcLogger := logger.NamedFromContext(s.Context(), logger.SubsystemGRPC)
+	if !grpcLogger.Core().Enabled(zapcore.DebugLevel) {
+		return
+	}
+
+	grpcLogger.Debug("stream message",
+		zap.String("direction", direction),
+		zap.Int("seq", seq),
+		zap.String("msg", redact.String(m)),
+	)
+}
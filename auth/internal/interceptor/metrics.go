@@ -2,30 +2,72 @@ package interceptor
 
 import (
 	"context"
-	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
-	"github.com/MercerMorning/go_example/auth/internal/logger"
 	"github.com/MercerMorning/go_example/auth/internal/metric"
 )
 
-func MetricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	logger.Info("fdfsdf")
-	metric.IncRequestCounter()
+// MetricsInterceptor считает unary вызовы по сервису, методу и коду ответа
+func MetricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+	done := metric.StartGRPC(metric.Unary, info.FullMethod)
+	defer finishMetrics(done, &err)
 
-	timeStart := time.Now()
+	return handler(ctx, req)
+}
+
+// StreamMetricsInterceptor считает потоковые вызовы и каждое сообщение в них
+func StreamMetricsInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	typ := streamType(info)
+	done := metric.StartGRPC(typ, info.FullMethod)
+	defer finishMetrics(done, &err)
+
+	return handler(srv, &metricsServerStream{ServerStream: ss, typ: typ, method: info.FullMethod})
+}
 
-	res, err := handler(ctx, req)
-	diffTime := time.Since(timeStart)
+// finishMetrics завершает учет вызова. Интерцептор стоит внутри RecoveryInterceptor, поэтому
+// паника обработчика учитывается с кодом Internal, который вернет клиенту recovery, и передается дальше.
+func finishMetrics(done func(error), err *error) {
+	if p := recover(); p != nil {
+		done(status.Error(codes.Internal, "panic"))
+		panic(p)
+	}
+	done(*err)
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return metric.BidiStream
+	case info.IsClientStream:
+		return metric.ClientStream
+	default:
+		return metric.ServerStream
+	}
+}
+
+type metricsServerStream struct {
+	grpc.ServerStream
+	typ    string
+	method string
+}
+
+func (s *metricsServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		metric.IncGRPCMsgSent(s.typ, s.method)
+	}
+
+	return err
+}
 
-	if err != nil {
-		metric.IncResponseCounter("error", info.FullMethod)
-		metric.HistogramResponseTimeObserve("error", diffTime.Seconds())
-	} else {
-		metric.IncResponseCounter("success", info.FullMethod)
-		metric.HistogramResponseTimeObserve("success", diffTime.Seconds())
+func (s *metricsServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		metric.IncGRPCMsgReceived(s.typ, s.method)
 	}
 
-	return res, err
+	return err
 }
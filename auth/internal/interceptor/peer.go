@@ -0,0 +1,34 @@
+package interceptor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/tlsconfig"
+)
+
+// PeerIdentityInterceptor добавляет субъект клиентского сертификата (mTLS) в логи и span.
+// Следующие интерцепторы получают его через tlsconfig.PeerIdentity.
+// Должен стоять после LogInterceptor.
+func PeerIdentityInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	annotatePeer(ctx)
+	return handler(ctx, req)
+}
+
+// StreamPeerIdentityInterceptor потоковая версия PeerIdentityInterceptor
+func StreamPeerIdentityInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	annotatePeer(ss.Context())
+	return handler(srv, ss)
+}
+
+func annotatePeer(ctx context.Context) {
+	if id, ok := tlsconfig.PeerIdentity(ctx); ok {
+		logger.AddFields(ctx, zap.String(logger.PeerKey, id.String()))
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("tls.client.subject", id.String()))
+	}
+}
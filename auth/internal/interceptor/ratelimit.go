@@ -0,0 +1,79 @@
+package interceptor
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+)
+
+// RateLimiter ограничивает частоту запросов к каждому gRPC методу отдельно.
+// Лимиты можно менять во время работы через Reload.
+type RateLimiter struct {
+	mu       sync.Mutex
+	cfg      config.RateLimit
+	limiters map[string]*rate.Limiter
+}
+
+// NewRateLimiter создает ограничитель с заданными лимитами
+func NewRateLimiter(cfg config.RateLimit) *RateLimiter {
+	return &RateLimiter{
+		cfg:      cfg,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// Reload применяет новые лимиты ко всем методам, сохраняя накопленные токены
+func (l *RateLimiter) Reload(cfg config.Reloadable) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cfg = cfg.RateLimit
+	for _, limiter := range l.limiters {
+		limiter.SetLimit(rate.Limit(l.cfg.RPS))
+		limiter.SetBurst(l.cfg.Burst)
+	}
+
+	return nil
+}
+
+// Unary отклоняет запрос с codes.ResourceExhausted, если лимит метода исчерпан
+func (l *RateLimiter) Unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !l.allow(info.FullMethod) {
+		return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", info.FullMethod)
+	}
+
+	return handler(ctx, req)
+}
+
+// Stream отклоняет поток с codes.ResourceExhausted, если лимит метода исчерпан.
+// Учитывается открытие потока, а не сообщения в нем.
+func (l *RateLimiter) Stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !l.allow(info.FullMethod) {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", info.FullMethod)
+	}
+
+	return handler(srv, ss)
+}
+
+func (l *RateLimiter) allow(method string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cfg.RPS == 0 {
+		return true
+	}
+
+	limiter, ok := l.limiters[method]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(l.cfg.RPS), l.cfg.Burst)
+		l.limiters[method] = limiter
+	}
+
+	return limiter.Allow()
+}
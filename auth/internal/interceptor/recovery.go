@@ -0,0 +1,60 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/metric"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+)
+
+// RecoveryInterceptor перехватывает панику обработчика и следующих интерцепторов:
+// пишет ее со стеком в лог и Reporter, учитывает в метрике и возвращает клиенту codes.Internal
+// с request ID, по которому запрос находится в логах. Должен стоять сразу после RequestIDInterceptor.
+func RecoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoverPanic(ctx, info.FullMethod, r)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// StreamRecoveryInterceptor потоковая версия RecoveryInterceptor
+func StreamRecoveryInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoverPanic(ss.Context(), info.FullMethod, r)
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+func recoverPanic(ctx context.Context, method string, r interface{}) error {
+	stack := string(debug.Stack())
+	id, _ := requestid.FromContext(ctx)
+
+	logger.FromContext(ctx).Error("Handler panicked",
+		zap.String(logger.MethodKey, method),
+		zap.Any("panic", r),
+		zap.String("stack", stack),
+		logger.Reported(),
+	)
+	reporter.CaptureError(ctx, fmt.Errorf("panic in %s: %v", method, r), reporter.Event{
+		Tags:  map[string]string{"method": method, "error_type": "panic"},
+		Extra: map[string]interface{}{"stack": stack},
+	})
+	metric.IncPanicRecovered("grpc")
+
+	return status.Errorf(codes.Internal, "internal error, request_id: %s", id)
+}
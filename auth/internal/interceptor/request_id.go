@@ -0,0 +1,65 @@
+package interceptor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+)
+
+// RequestIDInterceptor принимает x-request-id из метаданных или генерирует новый,
+// кладет его в контекст и span и возвращает клиенту в заголовке ответа.
+// Должен стоять после ServerTracingInterceptor и перед LogInterceptor.
+func RequestIDInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, id := requestIDContext(ctx)
+
+	err := grpc.SetHeader(ctx, metadata.Pairs(requestid.MetadataKey, id))
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamRequestIDInterceptor потоковая версия RequestIDInterceptor
+func StreamRequestIDInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, id := requestIDContext(ss.Context())
+
+	err := ss.SetHeader(metadata.Pairs(requestid.MetadataKey, id))
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, withContext(ss, ctx))
+}
+
+func requestIDContext(ctx context.Context) (context.Context, string) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestid.MetadataKey); len(values) > 0 && requestid.Valid(values[0]) {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = requestid.New()
+	}
+
+	ctx = requestid.ToContext(ctx, id)
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("request_id", id))
+
+	return ctx, id
+}
+
+// ClientRequestIDInterceptor передает идентификатор запроса в исходящие вызовы
+func ClientRequestIDInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id, ok := requestid.FromContext(ctx); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, requestid.MetadataKey, id)
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
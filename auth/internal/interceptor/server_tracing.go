@@ -3,25 +3,24 @@ package interceptor
 import (
 	"context"
 
-	"github.com/opentracing/opentracing-go"
-	"github.com/opentracing/opentracing-go/ext"
-	"github.com/uber/jaeger-client-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+
+	"github.com/MercerMorning/go_example/auth/internal/redact"
 )
 
 const traceIDKey = "x-trace-id"
 
+// ServerTracingInterceptor дополняет span вызова, который создает otelgrpc.NewServerHandler:
+// возвращает клиенту trace ID в заголовке x-trace-id и записывает в span ошибку или ответ
 func ServerTracingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-	span, ctx := opentracing.StartSpanFromContext(ctx, info.FullMethod)
-	defer span.Finish()
-
-	spanContext, ok := span.Context().(jaeger.SpanContext)
-	if ok {
-		ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs(traceIDKey, spanContext.TraceID().String()))
+	span := trace.SpanFromContext(ctx)
 
-		header := metadata.New(map[string]string{traceIDKey: spanContext.TraceID().String()})
-		err := grpc.SendHeader(ctx, header)
+	if sc := span.SpanContext(); sc.HasTraceID() {
+		err := grpc.SetHeader(ctx, metadata.Pairs(traceIDKey, sc.TraceID().String()))
 		if err != nil {
 			return nil, err
 		}
@@ -29,13 +28,37 @@ func ServerTracingInterceptor(ctx context.Context, req interface{}, info *grpc.U
 
 	res, err := handler(ctx, req)
 	if err != nil {
-		ext.Error.Set(span, true)
-		span.SetTag("err", err.Error())
-	} else {
-		// Ответ может быть большим, поэтому не стоит добавлять его в теги
-		// Здесь это лишь пример, как можно добавить ответ в тег
-		span.SetTag("res", res)
+		recordSpanError(span, err)
+	} else if span.IsRecording() {
+		// Ответ может быть большим и содержать чувствительные поля,
+		// поэтому в атрибут попадает только обрезанная копия со скрытыми полями
+		span.SetAttributes(attribute.String("rpc.response", redact.String(res)))
 	}
 
 	return res, err
 }
+
+// StreamServerTracingInterceptor потоковая версия ServerTracingInterceptor.
+// События сообщений потока записывает в span otelgrpc.NewServerHandler.
+func StreamServerTracingInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	span := trace.SpanFromContext(ss.Context())
+
+	if sc := span.SpanContext(); sc.HasTraceID() {
+		err := ss.SetHeader(metadata.Pairs(traceIDKey, sc.TraceID().String()))
+		if err != nil {
+			return err
+		}
+	}
+
+	err := handler(srv, ss)
+	if err != nil {
+		recordSpanError(span, err)
+	}
+
+	return err
+}
+
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
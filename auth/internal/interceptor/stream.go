@@ -0,0 +1,26 @@
+package interceptor
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// serverStream подменяет контекст потока, чтобы значения, добавленные интерцептором
+// (request ID, поля логгера, субъект), были видны следующим интерцепторам и обработчику
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func withContext(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	if ctx == ss.Context() {
+		return ss
+	}
+
+	return &serverStream{ServerStream: ss, ctx: ctx}
+}
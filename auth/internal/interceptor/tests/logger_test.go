@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/interceptor"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/monitoring"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+)
+
+func TestLogInterceptorReportsErrorOnce(t *testing.T) {
+	rec := reporter.NewRecorder()
+	reporter.Set(rec)
+	t.Cleanup(func() { reporter.Set(nil) })
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger.Init(zapcore.NewTee(core, logger.NewSentryCore(config.LoggerSentry{
+		Level:           "error",
+		BreadcrumbLevel: "info",
+		QueueSize:       10,
+	})))
+
+	var code codes.Code
+	failing := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return nil, status.Error(code, "failed")
+	}
+	failingStream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return status.Error(code, "failed")
+	}
+	mon := monitoring.NewMiddleware(rec, monitoring.Thresholds{})
+	client := serve(t,
+		grpc.ChainUnaryInterceptor(mon.GRPCUnaryInterceptor(), interceptor.LogInterceptor, failing),
+		grpc.ChainStreamInterceptor(mon.GRPCStreamInterceptor(), interceptor.StreamLogInterceptor, failingStream),
+	)
+
+	// Internal отправляет monitoring, запись лога его не дублирует
+	code = codes.Internal
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.NoError(t, logger.Sync())
+
+	errs := rec.Records(reporter.KindError)
+	require.Len(t, errs, 1)
+	require.Equal(t, "Internal", errs[0].Tags["grpc_code"])
+
+	// Вызов пишется одной записью с уровнем по коду
+	entries := logs.TakeAll()
+	require.Len(t, entries, 1)
+	require.Equal(t, "request", entries[0].Message)
+	require.Equal(t, zapcore.ErrorLevel, entries[0].Level)
+	require.Equal(t, err.Error(), entries[0].ContextMap()["error"])
+
+	// Ошибка клиента пишется предупреждением и не отправляется
+	code = codes.NotFound
+	_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	stream, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.NotFound, status.Code(err))
+	require.NoError(t, logger.Sync())
+
+	require.Len(t, rec.Records(reporter.KindError), 1)
+	entries = logs.TakeAll()
+	require.Len(t, entries, 2)
+	require.Equal(t, "request", entries[0].Message)
+	require.Equal(t, "stream", entries[1].Message)
+	for _, entry := range entries {
+		require.Equal(t, zapcore.WarnLevel, entry.Level)
+		require.Equal(t, "NotFound", entry.ContextMap()["grpc_code"])
+	}
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/interceptor"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+)
+
+func TestRecoveryReturnsInternalWithRequestID(t *testing.T) {
+	core, logs := observer.New(zapcore.ErrorLevel)
+	logger.Init(core)
+	reports := reporter.NewRecorder()
+	reporter.Set(reports)
+	t.Cleanup(func() { reporter.Set(nil) })
+
+	panicking := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		panic("nil map")
+	}
+	panickingStream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		panic("nil map")
+	}
+	client := serve(t,
+		grpc.ChainUnaryInterceptor(interceptor.RequestIDInterceptor, interceptor.RecoveryInterceptor, panicking),
+		grpc.ChainStreamInterceptor(interceptor.StreamRequestIDInterceptor, interceptor.StreamRecoveryInterceptor, panickingStream),
+	)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), requestid.MetadataKey, "req-1")
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Contains(t, status.Convert(err).Message(), "request_id: req-1")
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Internal, status.Code(err))
+
+	// Сервер продолжает работать, паника записана в лог со стеком и request ID
+	entries := logs.FilterMessage("Handler panicked").All()
+	require.Len(t, entries, 2)
+	require.Equal(t, "nil map", entries[0].ContextMap()["panic"])
+	require.Equal(t, "req-1", entries[0].ContextMap()[logger.RequestIDKey])
+	require.Contains(t, entries[0].ContextMap()["stack"], "runtime/debug.Stack")
+
+	// И отправлена в Reporter с тем же request ID
+	errs := reports.Records(reporter.KindError)
+	require.Len(t, errs, 2)
+	require.Equal(t, "panic in /grpc.health.v1.Health/Check: nil map", errs[0].Message)
+	require.Equal(t, "req-1", errs[0].Tags["request_id"])
+	require.Equal(t, "panic", errs[0].Tags["error_type"])
+}
@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/interceptor"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+)
+
+const watchMethod = "/grpc.health.v1.Health/Watch"
+
+func TestStreamChain(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger.Init(core)
+	require.NoError(t, logger.SetLevel("debug"))
+	t.Cleanup(func() { _ = logger.SetLevel("info") })
+
+	authorizer := interceptor.NewAuthorizer(config.Auth{
+		Tokens: []config.Token{{Token: "secret", Subject: "alice", Role: "admin"}},
+		Rules:  []config.Rule{{Method: watchMethod, Roles: []string{"admin"}}},
+	})
+	client := serve(t, grpc.ChainStreamInterceptor(
+		interceptor.StreamRequestIDInterceptor,
+		interceptor.StreamLogInterceptor,
+		interceptor.StreamMetricsInterceptor,
+		interceptor.NewRateLimiter(config.RateLimit{}).Stream,
+		authorizer.Stream,
+	))
+
+	// Без токена поток отклоняется до обработчика
+	_, err := watch(t, client, "")
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	header, err := watch(t, client, "Bearer secret")
+	require.NoError(t, err)
+	require.True(t, requestid.Valid(header.Get(requestid.MetadataKey)[0]))
+
+	messages := logs.FilterMessage("stream message").All()
+	require.Len(t, messages, 2)
+	require.Equal(t, "recv", messages[0].ContextMap()["direction"])
+	require.Equal(t, "send", messages[1].ContextMap()["direction"])
+	require.Contains(t, messages[1].ContextMap()["msg"], "SERVING")
+
+	// Поля, добавленные авторизацией, видны в итоговой записи потока
+	require.Eventually(t, func() bool {
+		return logs.FilterMessage("stream").FilterFieldKey(logger.UserIDKey).Len() == 1
+	}, time.Second, 10*time.Millisecond)
+	entry := logs.FilterMessage("stream").FilterFieldKey(logger.UserIDKey).All()[0]
+	require.Equal(t, "alice", entry.ContextMap()[logger.UserIDKey])
+	require.Equal(t, watchMethod, entry.ContextMap()[logger.MethodKey])
+	require.EqualValues(t, 1, entry.ContextMap()["sent"])
+}
+
+func TestStreamRateLimit(t *testing.T) {
+	logger.Init(zapcore.NewNopCore())
+
+	client := serve(t, grpc.ChainStreamInterceptor(
+		interceptor.NewRateLimiter(config.RateLimit{RPS: 0.001, Burst: 1}).Stream,
+	))
+
+	_, err := watch(t, client, "")
+	require.NoError(t, err)
+
+	_, err = watch(t, client, "")
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func serve(t *testing.T, opts ...grpc.ServerOption) healthpb.HealthClient {
+	lis := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+// watch открывает поток, читает первое сообщение и закрывает поток
+func watch(t *testing.T, client healthpb.HealthClient, authorization string) (metadata.MD, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if authorization != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", authorization)
+	}
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	if _, err = stream.Recv(); err != nil {
+		return nil, err
+	}
+
+	return stream.Header()
+}
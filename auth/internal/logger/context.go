@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+)
+
+// Ключи полей, которыми обогащается логгер запроса
+const (
+	TraceIDKey   = "trace_id"
+	SpanIDKey    = "span_id"
+	RequestIDKey = "request_id"
+	UserIDKey    = "user_id"
+	MethodKey    = "method"
+	PeerKey      = "peer"
+)
+
+type fieldsKey struct{}
+
+// requestFields поля запроса. Общие для всех контекстов, производных от запроса,
+// поэтому поля, добавленные глубже по цепочке (например, user_id после аутентификации),
+// видны и в логах внешних интерцепторов.
+type requestFields struct {
+	mu     sync.RWMutex
+	fields []zap.Field
+}
+
+// ToContext привязывает к контексту набор полей запроса
+func ToContext(ctx context.Context, fields ...zap.Field) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, &requestFields{fields: fields})
+}
+
+// AddFields добавляет поля в набор запроса. Без ToContext поля добавляются в новый контекст.
+func AddFields(ctx context.Context, fields ...zap.Field) context.Context {
+	rf, ok := ctx.Value(fieldsKey{}).(*requestFields)
+	if !ok {
+		return ToContext(ctx, fields...)
+	}
+
+	rf.mu.Lock()
+	rf.fields = append(rf.fields, fields...)
+	rf.mu.Unlock()
+
+	return ctx
+}
+
+// FromContext возвращает общий логгер с полями запроса, request ID, trace ID и span ID текущего span.
+// SentryCore отправляет записи такого логгера в hub запроса.
+func FromContext(ctx context.Context) *zap.Logger {
+	l := globalLogger
+	if l == nil {
+		l = zap.NewNop()
+	}
+
+	return withContextFields(ctx, l)
+}
+
+// NamedFromContext возвращает логгер подсистемы с полями запроса
+func NamedFromContext(ctx context.Context, name string) *zap.Logger {
+	return withContextFields(ctx, Named(name))
+}
+
+func withContextFields(ctx context.Context, l *zap.Logger) *zap.Logger {
+	fields := []zap.Field{contextField(ctx)}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields,
+			zap.String(TraceIDKey, sc.TraceID().String()),
+			zap.String(SpanIDKey, sc.SpanID().String()),
+		)
+	}
+
+	if id, ok := requestid.FromContext(ctx); ok {
+		fields = append(fields, zap.String(RequestIDKey, id))
+	}
+
+	if rf, ok := ctx.Value(fieldsKey{}).(*requestFields); ok {
+		rf.mu.RLock()
+		fields = append(fields, rf.fields...)
+		rf.mu.RUnlock()
+	}
+
+	return l.With(fields...)
+}
@@ -1,20 +1,84 @@
 package logger
 
 import (
+	"fmt"
+	"sync/atomic"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var globalLogger *zap.Logger
+// Подсистемы, уровень логирования которых настраивается отдельно от общего
+const (
+	SubsystemDB     = "db"
+	SubsystemGRPC   = "grpc"
+	SubsystemHTTP   = "http"
+	SubsystemOutbox = "outbox"
+)
+
+// Subsystems список подсистем с отдельным уровнем логирования
+var Subsystems = []string{SubsystemDB, SubsystemGRPC, SubsystemHTTP, SubsystemOutbox}
+
+var (
+	globalLogger *zap.Logger
+	globalLevel  = zap.NewAtomicLevel()
+	subsystems   = newSubsystems()
+)
+
+// subsystem дочерний логгер со своим уровнем. Пока уровень не задан, используется общий.
+type subsystem struct {
+	level  zap.AtomicLevel
+	custom atomic.Bool
+	logger *zap.Logger
+}
+
+func (s *subsystem) Enabled(level zapcore.Level) bool {
+	if s.custom.Load() {
+		return s.level.Enabled(level)
+	}
+	return globalLevel.Enabled(level)
+}
+
+// SubsystemLevel уровень логирования подсистемы
+type SubsystemLevel struct {
+	Level     string
+	Inherited bool
+}
 
+// Init создает общий логгер и логгеры подсистем поверх core.
+// Уровень фильтруется здесь, поэтому core должен пропускать все уровни.
 func Init(core zapcore.Core, options ...zap.Option) {
-	globalLogger = zap.New(core, options...)
+	globalLogger = zap.New(&levelCore{Core: core, enabler: globalLevel}, options...)
+
+	for name, s := range subsystems {
+		s.logger = zap.New(&levelCore{Core: core, enabler: s}, options...).Named(name)
+	}
 }
 
 func Get() *zap.Logger {
 	return globalLogger
 }
 
+// Named возвращает логгер подсистемы. До Init возвращает логгер, который ничего не пишет.
+func Named(name string) *zap.Logger {
+	if s, ok := subsystems[name]; ok && s.logger != nil {
+		return s.logger
+	}
+	if globalLogger == nil {
+		return zap.NewNop()
+	}
+
+	return globalLogger.Named(name)
+}
+
+// Sync записывает буферы общего логгера и ждет отправки ошибок из очереди SentryCore
+func Sync() error {
+	if globalLogger == nil {
+		return nil
+	}
+	return globalLogger.Sync()
+}
+
 func Debug(msg string, fields ...zap.Field) {
 	globalLogger.Debug(msg, fields...)
 }
@@ -38,3 +102,78 @@ func Fatal(msg string, fields ...zap.Field) {
 func WithOptions(opts ...zap.Option) *zap.Logger {
 	return globalLogger.WithOptions(opts...)
 }
+
+// Level возвращает общий уровень логирования, который можно менять во время работы
+func Level() zap.AtomicLevel {
+	return globalLevel
+}
+
+// SetLevel меняет уровень логирования
+func SetLevel(level string) error {
+	return globalLevel.UnmarshalText([]byte(level))
+}
+
+// SetSubsystemLevel меняет уровень логирования подсистемы.
+// Пустой уровень возвращает подсистему к общему уровню.
+func SetSubsystemLevel(name, level string) error {
+	s, ok := subsystems[name]
+	if !ok {
+		return fmt.Errorf("unknown logger subsystem %q", name)
+	}
+
+	if level == "" {
+		s.custom.Store(false)
+		return nil
+	}
+
+	if err := s.level.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+	s.custom.Store(true)
+
+	return nil
+}
+
+// SubsystemLevels возвращает действующие уровни логирования подсистем
+func SubsystemLevels() map[string]SubsystemLevel {
+	levels := make(map[string]SubsystemLevel, len(subsystems))
+	for name, s := range subsystems {
+		if s.custom.Load() {
+			levels[name] = SubsystemLevel{Level: s.level.String()}
+			continue
+		}
+		levels[name] = SubsystemLevel{Level: globalLevel.String(), Inherited: true}
+	}
+
+	return levels
+}
+
+func newSubsystems() map[string]*subsystem {
+	m := make(map[string]*subsystem, len(Subsystems))
+	for _, name := range Subsystems {
+		m[name] = &subsystem{level: zap.NewAtomicLevel()}
+	}
+
+	return m
+}
+
+// levelCore отбрасывает записи ниже уровня enabler до передачи во вложенный core
+type levelCore struct {
+	zapcore.Core
+	enabler zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.enabler.Enabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), enabler: c.enabler}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.enabler.Enabled(entry.Level) {
+		return checked
+	}
+	return c.Core.Check(entry, checked)
+}
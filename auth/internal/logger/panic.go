@@ -1,31 +1,27 @@
 package logger
 
 import (
+	"context"
+	"fmt"
 	"runtime/debug"
 
-	"github.com/getsentry/sentry-go"
 	"go.uber.org/zap"
+
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
 )
 
-// RecoverPanic восстанавливается от паники и отправляет её в Sentry
+// RecoverPanic восстанавливается от паники и отправляет её в Reporter
 func RecoverPanic() {
 	if r := recover(); r != nil {
 		// Логируем панику
 		Error("Panic recovered",
 			zap.Any("panic", r),
 			zap.String("stack", string(debug.Stack())),
+			Reported(),
 		)
 
-		// Отправляем в Sentry
-		sentry.WithScope(func(scope *sentry.Scope) {
-			scope.SetLevel(sentry.LevelFatal)
-			scope.SetTag("error_type", "panic")
-			scope.SetContext("panic_info", map[string]interface{}{
-				"panic_value": r,
-				"stack":       string(debug.Stack()),
-			})
-			sentry.CaptureException(&panicError{value: r})
-		})
+		// Отправляем в Reporter
+		capturePanic(r)
 
 		// Re-panic чтобы приложение завершилось
 		panic(r)
@@ -39,28 +35,35 @@ func RecoverPanicSilent() {
 		Error("Panic recovered (silent)",
 			zap.Any("panic", r),
 			zap.String("stack", string(debug.Stack())),
+			Reported(),
 		)
 
-		// Отправляем в Sentry
-		sentry.WithScope(func(scope *sentry.Scope) {
-			scope.SetLevel(sentry.LevelFatal)
-			scope.SetTag("error_type", "panic")
-			scope.SetContext("panic_info", map[string]interface{}{
-				"panic_value": r,
-				"stack":       string(debug.Stack()),
-			})
-			sentry.CaptureException(&panicError{value: r})
-		})
+		// Отправляем в Reporter
+		capturePanic(r)
 	}
 }
 
+// capturePanic отправляет панику в Reporter по умолчанию
+func capturePanic(r interface{}) {
+	reporter.CaptureError(context.Background(), &panicError{value: r}, reporter.Event{
+		Level: reporter.LevelFatal,
+		Tags:  map[string]string{"error_type": "panic"},
+		Extra: map[string]interface{}{
+			"panic_info": map[string]interface{}{
+				"panic_value": fmt.Sprint(r),
+				"stack":       string(debug.Stack()),
+			},
+		},
+	})
+}
+
 // panicError - кастомный тип ошибки для паник
 type panicError struct {
 	value interface{}
 }
 
 func (e *panicError) Error() string {
-	return "panic occurred"
+	return fmt.Sprintf("panic occurred: %v", e.value)
 }
 
 // WithPanicRecovery оборачивает функцию с перехватом паник
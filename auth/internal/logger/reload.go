@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"sync"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+)
+
+// configured уровни из последней примененной конфигурации. Reload меняет только уровни,
+// измененные в конфигурации, поэтому уровень, заданный во время работы через admin API,
+// сохраняется до изменения того же уровня в конфигурации или сброса через admin API.
+var configured struct {
+	sync.Mutex
+	loaded     bool
+	level      string
+	subsystems map[string]string
+}
+
+// Reload применяет перезагружаемые настройки логирования
+func Reload(cfg config.Reloadable) error {
+	configured.Lock()
+	defer configured.Unlock()
+
+	if !configured.loaded || cfg.LogLevel != configured.level {
+		if err := SetLevel(cfg.LogLevel); err != nil {
+			return err
+		}
+	}
+
+	levels := cfg.LogSubsystems.Levels()
+	for name, level := range levels {
+		if configured.loaded && level == configured.subsystems[name] {
+			continue
+		}
+		if err := SetSubsystemLevel(name, level); err != nil {
+			return err
+		}
+	}
+
+	configured.loaded = true
+	configured.level = cfg.LogLevel
+	configured.subsystems = levels
+
+	return nil
+}
+
+// ResetSubsystemLevel возвращает подсистеме уровень из конфигурации,
+// а если он не задан — общий уровень
+func ResetSubsystemLevel(name string) error {
+	configured.Lock()
+	defer configured.Unlock()
+
+	return SetSubsystemLevel(name, configured.subsystems[name])
+}
@@ -2,180 +2,388 @@ package logger
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+)
+
+// Ключи служебных полей SentryCore. Поля типа SkipType не попадают в вывод других core.
+const (
+	contextFieldKey  = "_context"
+	reportedFieldKey = "_reported"
+)
+
+// Ограничения Sentry на теги: поля с более длинным ключом или значением отправляются в extra
+const (
+	maxTagKeyLength   = 32
+	maxTagValueLength = 200
+)
+
+const (
+	// maxStackDepth глубина стека вызова логгера в событии
+	maxStackDepth = 64
+	// fatalFlushTimeout сколько ждать отправки записи, после которой процесс может завершиться
+	fatalFlushTimeout = 2 * time.Second
 )
 
-// SentryCore - кастомный core для интеграции с Sentry
+var errQueueFull = errors.New("sentry core: queue is full, log entry dropped")
+
+// Reported помечает запись, ошибка которой уже отправлена в Reporter,
+// чтобы SentryCore не отправил ее второй раз
+func Reported() zap.Field {
+	return zap.Field{Key: reportedFieldKey, Type: zapcore.SkipType}
+}
+
+// contextField передает SentryCore контекст запроса: breadcrumbs и ошибки уходят в его hub
+func contextField(ctx context.Context) zap.Field {
+	return zap.Field{Key: contextFieldKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// SentryCore core, который отправляет записи лога в Reporter по умолчанию (reporter.Get).
+// Записи от Level отправляются ошибками: сообщение записи, цепочка из полей error и стек
+// вызова логгера. Записи от BreadcrumbLevel до Level становятся breadcrumbs в hub запроса
+// из logger.FromContext. Ставится рядом с основным core через zapcore.NewTee.
 type SentryCore struct {
-	zapcore.Core
-	level zapcore.Level
+	level           zapcore.Level
+	breadcrumbLevel zapcore.Level
+	queue           *reportQueue
+
+	ctx      context.Context
+	fields   []zapcore.Field
+	reported bool
 }
 
-// NewSentryCore создает новый Sentry core
-func NewSentryCore(core zapcore.Core, level zapcore.Level) *SentryCore {
+// NewSentryCore создает core по секции logger.sentry. Ошибки отправляются в фоне
+// через очередь размера QueueSize, Sync ждет отправки очереди.
+func NewSentryCore(cfg config.LoggerSentry) *SentryCore {
+	level, err := zapcore.ParseLevel(cfg.Level)
+	if err != nil {
+		level = zapcore.ErrorLevel
+	}
+	breadcrumbLevel, err := zapcore.ParseLevel(cfg.BreadcrumbLevel)
+	if err != nil {
+		breadcrumbLevel = zapcore.InfoLevel
+	}
+
 	return &SentryCore{
-		Core:  core,
-		level: level,
+		level:           level,
+		breadcrumbLevel: min(breadcrumbLevel, level),
+		queue:           newReportQueue(cfg.QueueSize),
+		ctx:             context.Background(),
 	}
 }
 
-// Write отправляет логи в Sentry
+func (c *SentryCore) Enabled(level zapcore.Level) bool {
+	return level >= c.breadcrumbLevel
+}
+
+func (c *SentryCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = clone.appendFields(append([]zapcore.Field(nil), c.fields...), fields)
+	return &clone
+}
+
+func (c *SentryCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
 func (c *SentryCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
-	// Сначала записываем в оригинальный core
-	if err := c.Core.Write(entry, fields); err != nil {
-		return err
+	write := *c
+	all := write.appendFields(append([]zapcore.Field(nil), c.fields...), fields)
+
+	if entry.Level < c.level {
+		reporter.AddBreadcrumb(write.ctx, reporter.Breadcrumb{
+			Category:  breadcrumbCategory(entry),
+			Message:   entry.Message,
+			Level:     reportLevel(entry.Level),
+			Data:      encodeFields(all),
+			Timestamp: entry.Time,
+		})
+		return nil
+	}
+	if write.reported {
+		return nil
 	}
 
-	// Если уровень лога подходящий, отправляем в Sentry
-	if entry.Level >= c.level {
-		c.sendToSentry(entry, fields)
+	// Поля кодируются сразу: значения zap.Object, zap.Stringer и zap.Any могут измениться
+	// после возврата из Write, а отправляет запись горутина очереди. Ошибки передаются
+	// как есть, чтобы Sentry видел их типы и коды gRPC; ошибки считаются неизменяемыми.
+	report := newLogReport(write.ctx, entry, all, callers())
+	if entry.Level > zapcore.ErrorLevel {
+		// После DPanic, Panic и Fatal процесс может завершиться: очередь и запись
+		// отправляются сразу
+		c.queue.sync()
+		report.send()
+		reporter.Flush(fatalFlushTimeout)
+		return nil
 	}
 
-	return nil
+	return c.queue.push(report)
 }
 
-// sendToSentry отправляет лог в Sentry
-func (c *SentryCore) sendToSentry(entry zapcore.Entry, fields []zapcore.Field) {
-	// Создаем scope для Sentry
-	scope := sentry.NewScope()
+// Sync ждет, пока записи из очереди будут переданы в Reporter
+func (c *SentryCore) Sync() error {
+	c.queue.sync()
+	return nil
+}
 
-	// Добавляем поля как теги и контекст
+// appendFields добавляет поля записи, забирая из них служебные поля контекста и пометки Reported
+func (c *SentryCore) appendFields(dst, fields []zapcore.Field) []zapcore.Field {
 	for _, field := range fields {
-		switch field.Type {
-		case zapcore.StringType:
-			scope.SetTag(field.Key, field.String)
-		case zapcore.Int64Type:
-			scope.SetTag(field.Key, string(rune(field.Integer)))
-		case zapcore.BoolType:
-			scope.SetTag(field.Key, string(rune(field.Integer)))
-		default:
-			scope.SetContext(field.Key, map[string]interface{}{"value": field.Interface})
+		if field.Type != zapcore.SkipType {
+			dst = append(dst, field)
+			continue
 		}
-	}
 
-	// Устанавливаем уровень
-	level := sentry.LevelInfo
-	switch entry.Level {
-	case zapcore.DebugLevel:
-		level = sentry.LevelDebug
-	case zapcore.InfoLevel:
-		level = sentry.LevelInfo
-	case zapcore.WarnLevel:
-		level = sentry.LevelWarning
-	case zapcore.ErrorLevel:
-		level = sentry.LevelError
-	case zapcore.FatalLevel, zapcore.PanicLevel:
-		level = sentry.LevelFatal
-	}
-
-	// Отправляем сообщение в Sentry
-	if entry.Level >= zapcore.ErrorLevel {
-		// Для ошибок создаем exception
-		sentry.WithScope(func(scope *sentry.Scope) {
-			scope.SetLevel(level)
-			scope.SetTag("logger", "zap")
-			scope.SetContext("log_entry", map[string]interface{}{
-				"message": entry.Message,
-				"time":    entry.Time,
-				"caller":  entry.Caller.String(),
-			})
-
-			// Добавляем поля
-			for _, field := range fields {
-				scope.SetContext(field.Key, map[string]interface{}{"value": field.Interface})
+		switch field.Key {
+		case contextFieldKey:
+			if ctx, ok := field.Interface.(context.Context); ok {
+				c.ctx = ctx
 			}
+		case reportedFieldKey:
+			c.reported = true
+		}
+	}
+	return dst
+}
 
-			sentry.CaptureException(&sentryException{
-				message: entry.Message,
-				time:    entry.Time,
-			})
-		})
-	} else {
-		// Для остальных уровней отправляем как breadcrumb
-		sentry.AddBreadcrumb(&sentry.Breadcrumb{
-			Message:   entry.Message,
-			Level:     level,
-			Timestamp: entry.Time,
-			Data: map[string]interface{}{
-				"logger": entry.LoggerName,
-				"caller": entry.Caller.String(),
-			},
-		})
+// logReport закодированная запись лога, ожидающая отправки. done != nil — метка,
+// которой Sync ждет очередь.
+type logReport struct {
+	ctx   context.Context
+	err   *entryError
+	event reporter.Event
+	done  chan struct{}
+}
+
+func newLogReport(ctx context.Context, entry zapcore.Entry, fields []zapcore.Field, stack []uintptr) logReport {
+	tags, extra, errs := convertFields(fields)
+	tags["logger"] = "zap"
+	extra["log_entry"] = map[string]interface{}{
+		"message": entry.Message,
+		"time":    entry.Time,
+		"caller":  entry.Caller.String(),
+		"logger":  entry.LoggerName,
+	}
+
+	cause := errors.Join(errs...)
+	if len(errs) == 1 {
+		cause = errs[0]
+	}
+
+	return logReport{
+		ctx:   ctx,
+		err:   &entryError{message: entry.Message, cause: cause, stack: stack},
+		event: reporter.Event{Level: reportLevel(entry.Level), Tags: tags, Extra: extra},
+	}
+}
+
+func (r logReport) send() {
+	reporter.CaptureError(r.ctx, r.err, r.event)
+}
+
+// reportQueue очередь записей, которые фоновый обработчик передает в Reporter,
+// чтобы запись лога не ждала Sentry SDK
+type reportQueue struct {
+	reports chan logReport
+}
+
+func newReportQueue(size int) *reportQueue {
+	if size <= 0 {
+		size = 1
+	}
+
+	q := &reportQueue{reports: make(chan logReport, size)}
+	go q.run()
+
+	return q
+}
+
+// push добавляет запись без ожидания. Запись, не поместившаяся в очередь, отбрасывается:
+// zap выводит ошибку в ErrorOutput.
+func (q *reportQueue) push(report logReport) error {
+	select {
+	case q.reports <- report:
+		return nil
+	default:
+		return errQueueFull
+	}
+}
+
+func (q *reportQueue) sync() {
+	done := make(chan struct{})
+	q.reports <- logReport{done: done}
+	<-done
+}
+
+func (q *reportQueue) run() {
+	for report := range q.reports {
+		if report.done != nil {
+			close(report.done)
+			continue
+		}
+		report.send()
 	}
 }
 
-// sentryException - кастомный тип исключения для Sentry
-type sentryException struct {
+// entryError ошибка записи лога: сообщение записи, цепочка ошибок из полей error
+// и стек вызова логгера
+type entryError struct {
 	message string
-	time    time.Time
+	cause   error
+	stack   []uintptr
 }
 
-func (e *sentryException) Error() string {
+func (e *entryError) Error() string {
 	return e.message
 }
 
-// InitSentry инициализирует Sentry
-func InitSentry(dsn, environment, release string, debug bool, sampleRate, tracesSampleRate float64) error {
-	return sentry.Init(sentry.ClientOptions{
-		Dsn:              dsn,
-		Environment:      environment,
-		Release:          release,
-		Debug:            debug,
-		SampleRate:       sampleRate,
-		TracesSampleRate: tracesSampleRate,
-		BeforeSend: func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
-			// Можно добавить фильтрацию событий здесь
-			return event
-		},
-	})
-}
-
-// FlushSentry принудительно отправляет все события в Sentry
-func FlushSentry(timeout time.Duration) bool {
-	return sentry.Flush(timeout)
-}
-
-// CaptureError отправляет ошибку в Sentry
-func CaptureError(err error, tags map[string]string, context map[string]interface{}) {
-	sentry.WithScope(func(scope *sentry.Scope) {
-		// Добавляем теги
-		for key, value := range tags {
-			scope.SetTag(key, value)
-		}
+func (e *entryError) Unwrap() error {
+	return e.cause
+}
 
-		// Добавляем контекст
-		for key, value := range context {
-			scope.SetContext(key, map[string]interface{}{"value": value})
+// StackTrace возвращает стек в виде, который Sentry SDK читает у ошибок github.com/pkg/errors
+func (e *entryError) StackTrace() []uintptr {
+	return e.stack
+}
+
+// callers возвращает стек вызова логгера без кадров zap и пакета logger
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	pcs = pcs[:runtime.Callers(3, pcs)]
+
+	for len(pcs) > 0 {
+		fn := runtime.FuncForPC(pcs[0] - 1)
+		if fn == nil || !loggerFrame(fn.Name()) {
+			break
 		}
+		pcs = pcs[1:]
+	}
 
-		sentry.CaptureException(err)
-	})
+	return pcs
 }
 
-// CaptureMessage отправляет сообщение в Sentry
-func CaptureMessage(message string, level sentry.Level, tags map[string]string, context map[string]interface{}) {
-	sentry.WithScope(func(scope *sentry.Scope) {
-		scope.SetLevel(level)
+func loggerFrame(function string) bool {
+	return strings.HasPrefix(function, "go.uber.org/zap") ||
+		strings.HasPrefix(function, "github.com/MercerMorning/go_example/auth/internal/logger.")
+}
+
+// convertFields раскладывает поля записи: строки, bool и целые — в теги, ошибки — в цепочку
+// исключения, остальное и поля внутри zap.Namespace — в extra с исходными типами.
+// Числа с плавающей точкой, длительности и время почти всегда уникальны, поэтому
+// в тегах они только засоряли бы поиск по значениям.
+func convertFields(fields []zapcore.Field) (map[string]string, map[string]interface{}, []error) {
+	tags := make(map[string]string)
+	enc := newSnapshotEncoder()
 
-		// Добавляем теги
-		for key, value := range tags {
-			scope.SetTag(key, value)
+	var (
+		errs   []error
+		nested bool
+	)
+	for _, field := range fields {
+		switch field.Type {
+		case zapcore.ErrorType:
+			if err, ok := field.Interface.(error); ok {
+				errs = append(errs, err)
+				continue
+			}
+		case zapcore.NamespaceType:
+			nested = true
 		}
 
-		// Добавляем контекст
-		for key, value := range context {
-			scope.SetContext(key, map[string]interface{}{"value": value})
+		if !nested {
+			if value, ok := tagValue(field); ok && len(field.Key) <= maxTagKeyLength && len(value) <= maxTagValueLength {
+				tags[field.Key] = value
+				continue
+			}
 		}
+		field.AddTo(enc)
+	}
+
+	return tags, enc.Fields, errs
+}
 
-		sentry.CaptureMessage(message)
-	})
+// encodeFields возвращает поля записи с исходными типами
+func encodeFields(fields []zapcore.Field) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	enc := newSnapshotEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	return enc.Fields
 }
 
-// StartTransaction создает новую транзакцию для Sentry
-func StartTransaction(ctx context.Context, name, operation string) *sentry.Span {
-	transaction := sentry.StartTransaction(ctx, name)
-	return transaction
+// tagValue форматирует поле строки, bool или целого числа так же, как его выводит JSON encoder zap
+func tagValue(field zapcore.Field) (string, bool) {
+	switch field.Type {
+	case zapcore.StringType:
+		return field.String, true
+	case zapcore.BoolType:
+		return strconv.FormatBool(field.Integer == 1), true
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		return strconv.FormatInt(field.Integer, 10), true
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type:
+		return strconv.FormatUint(uint64(field.Integer), 10), true
+	}
+	return "", false
+}
+
+// snapshotEncoder кодирует поля в карту, копируя значения zap.Reflect (zap.Any с картами,
+// срезами и структурами) через JSON: MapObjectEncoder сохранил бы ссылку на исходное значение
+type snapshotEncoder struct {
+	*zapcore.MapObjectEncoder
+}
+
+func newSnapshotEncoder() snapshotEncoder {
+	return snapshotEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder()}
+}
+
+func (e snapshotEncoder) AddReflected(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var snapshot interface{}
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	return e.MapObjectEncoder.AddReflected(key, snapshot)
+}
+
+func breadcrumbCategory(entry zapcore.Entry) string {
+	if entry.LoggerName != "" {
+		return "log." + entry.LoggerName
+	}
+	return "log"
+}
+
+func reportLevel(level zapcore.Level) reporter.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return reporter.LevelDebug
+	case level == zapcore.InfoLevel:
+		return reporter.LevelInfo
+	case level == zapcore.WarnLevel:
+		return reporter.LevelWarning
+	case level == zapcore.ErrorLevel:
+		return reporter.LevelError
+	default:
+		return reporter.LevelFatal
+	}
 }
@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+)
+
+func TestFromContext(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger.Init(core)
+
+	provider := sdktrace.NewTracerProvider()
+	defer provider.Shutdown(context.Background())
+
+	ctx, span := provider.Tracer("test").Start(context.Background(), "/user_v1.UserV1/Get")
+	defer span.End()
+
+	ctx = logger.ToContext(ctx, zap.String(logger.MethodKey, "/user_v1.UserV1/Get"))
+
+	// Поля, добавленные в производном контексте, видны через исходный
+	logger.AddFields(context.WithValue(ctx, struct{}{}, 1), zap.String(logger.UserIDKey, "alice"))
+
+	logger.FromContext(ctx).Info("request")
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	sc := span.SpanContext()
+	require.Equal(t, sc.TraceID().String(), fields[logger.TraceIDKey])
+	require.Equal(t, sc.SpanID().String(), fields[logger.SpanIDKey])
+	require.Equal(t, "/user_v1.UserV1/Get", fields[logger.MethodKey])
+	require.Equal(t, "alice", fields[logger.UserIDKey])
+}
@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+)
+
+func TestReloadKeepsRuntimeLevels(t *testing.T) {
+	t.Cleanup(func() {
+		require.NoError(t, logger.Reload(config.Reloadable{LogLevel: "info"}))
+		require.NoError(t, logger.SetLevel("info"))
+		require.NoError(t, logger.SetSubsystemLevel(logger.SubsystemDB, ""))
+	})
+
+	cfg := config.Reloadable{LogLevel: "info", LogSubsystems: config.LoggerSubsystems{DB: "error"}}
+	require.NoError(t, logger.Reload(cfg))
+
+	// Уровни, заданные во время работы, переживают перезагрузку без их изменения в конфигурации
+	require.NoError(t, logger.SetLevel("debug"))
+	require.NoError(t, logger.SetSubsystemLevel(logger.SubsystemDB, "debug"))
+	require.NoError(t, logger.Reload(cfg))
+	require.Equal(t, "debug", logger.Level().String())
+	require.Equal(t, "debug", logger.SubsystemLevels()[logger.SubsystemDB].Level)
+
+	// Сброс возвращает подсистеме уровень из конфигурации
+	require.NoError(t, logger.ResetSubsystemLevel(logger.SubsystemDB))
+	require.Equal(t, logger.SubsystemLevel{Level: "error"}, logger.SubsystemLevels()[logger.SubsystemDB])
+
+	// Измененный в конфигурации уровень применяется
+	cfg.LogLevel = "warn"
+	require.NoError(t, logger.Reload(cfg))
+	require.Equal(t, "warn", logger.Level().String())
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+)
+
+func initSentryCore(t *testing.T) (*reporter.Recorder, *observer.ObservedLogs) {
+	t.Helper()
+
+	rec := reporter.NewRecorder()
+	reporter.Set(rec)
+	t.Cleanup(func() { reporter.Set(nil) })
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger.Init(zapcore.NewTee(core, logger.NewSentryCore(config.LoggerSentry{
+		Level:           "error",
+		BreadcrumbLevel: "info",
+		QueueSize:       10,
+	})))
+	require.NoError(t, logger.SetLevel("debug"))
+	t.Cleanup(func() { _ = logger.SetLevel("info") })
+
+	return rec, logs
+}
+
+func TestSentryCoreMapsFields(t *testing.T) {
+	rec, logs := initSentryCore(t)
+	errDB := errors.New("connection refused")
+
+	logger.Error("Failed to get user",
+		zap.Int64("user_id", 42),
+		zap.Bool("retry", true),
+		zap.Float64("ratio", 0.25),
+		zap.Uint8("attempt", 3),
+		zap.Duration("took", 1500*time.Millisecond),
+		zap.Strings("roles", []string{"admin"}),
+		zap.Error(fmt.Errorf("get user: %w", errDB)),
+	)
+	require.NoError(t, logger.Sync())
+	require.Equal(t, 1, logs.Len())
+
+	errs := rec.Records(reporter.KindError)
+	require.Len(t, errs, 1)
+	require.Equal(t, reporter.LevelError, errs[0].Level)
+	require.Equal(t, "Failed to get user", errs[0].Message)
+	require.Equal(t, map[string]string{
+		"logger":  "zap",
+		"user_id": "42",
+		"retry":   "true",
+		"attempt": "3",
+	}, errs[0].Tags)
+	// Дробные числа и длительности почти всегда уникальны и идут в extra
+	require.Equal(t, 0.25, errs[0].Extra["ratio"])
+	require.Equal(t, 1500*time.Millisecond, errs[0].Extra["took"])
+	require.Equal(t, []interface{}{"admin"}, errs[0].Extra["roles"])
+
+	// Цепочка исключения: запись лога -> ошибка из поля error -> ее причина
+	require.ErrorIs(t, errs[0].Err, errDB)
+	require.Equal(t, "get user: connection refused", errors.Unwrap(errs[0].Err).Error())
+
+	// Стек начинается с места вызова логгера, а не внутри zap
+	stack, ok := errs[0].Err.(interface{ StackTrace() []uintptr })
+	require.True(t, ok)
+	require.NotEmpty(t, stack.StackTrace())
+	frame, _ := runtime.CallersFrames(stack.StackTrace()).Next()
+	require.Contains(t, frame.Function, "TestSentryCoreMapsFields")
+}
+
+func TestSentryCoreBreadcrumbsUseRequestScope(t *testing.T) {
+	rec, _ := initSentryCore(t)
+
+	ctx := rec.NewScope(context.Background(), reporter.Scope{
+		Tags: map[string]string{"grpc.method": "/user_v1.UserV1/Get"},
+	})
+	logger.FromContext(ctx).Debug("cache miss")
+	logger.FromContext(ctx).Info("User loaded", zap.Int64("id", 7))
+	logger.NamedFromContext(ctx, logger.SubsystemDB).Warn("Slow query", zap.String("query", "user_repository.Get"))
+
+	breadcrumbs := rec.Records(reporter.KindBreadcrumb)
+	require.Len(t, breadcrumbs, 2)
+	require.Equal(t, "User loaded", breadcrumbs[0].Message)
+	require.Equal(t, "log", breadcrumbs[0].Category)
+	require.Equal(t, reporter.LevelInfo, breadcrumbs[0].Level)
+	require.Equal(t, "/user_v1.UserV1/Get", breadcrumbs[0].Tags["grpc.method"])
+	require.EqualValues(t, 7, breadcrumbs[0].Extra["id"])
+	require.Equal(t, "log.db", breadcrumbs[1].Category)
+	require.Equal(t, reporter.LevelWarning, breadcrumbs[1].Level)
+
+	logger.FromContext(ctx).Error("Request failed")
+	require.NoError(t, logger.Sync())
+	errs := rec.Records(reporter.KindError)
+	require.Len(t, errs, 1)
+	require.Equal(t, "/user_v1.UserV1/Get", errs[0].Tags["grpc.method"])
+	require.Nil(t, errors.Unwrap(errs[0].Err))
+}
+
+// counter изменяемое значение, которое пишется в лог через zap.Stringer, zap.Object и zap.Any
+type counter struct {
+	N int `json:"n"`
+}
+
+func (c *counter) String() string {
+	return strconv.Itoa(c.N)
+}
+
+func (c *counter) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("n", c.N)
+	return nil
+}
+
+func TestSentryCoreEncodesFieldsOnWrite(t *testing.T) {
+	rec, _ := initSentryCore(t)
+
+	c := &counter{N: 1}
+	values := map[string]int{"n": 1}
+	logger.Error("Request failed",
+		zap.Stringer("stringer", c),
+		zap.Object("object", c),
+		zap.Any("reflected", values),
+	)
+	// Значения меняются до того, как очередь отправит запись
+	c.N = 2
+	values["n"] = 2
+	require.NoError(t, logger.Sync())
+
+	errs := rec.Records(reporter.KindError)
+	require.Len(t, errs, 1)
+	require.Equal(t, "1", errs[0].Extra["stringer"])
+	require.Equal(t, map[string]interface{}{"n": 1}, errs[0].Extra["object"])
+	require.Equal(t, map[string]interface{}{"n": 1.0}, errs[0].Extra["reflected"])
+}
+
+func TestSentryCoreSkipsReportedEntries(t *testing.T) {
+	rec, logs := initSentryCore(t)
+
+	logger.Error("Handler panicked", zap.String("panic", "nil map"), logger.Reported())
+	require.NoError(t, logger.Sync())
+
+	require.Equal(t, 1, logs.Len())
+	require.Empty(t, rec.Records(reporter.KindError))
+}
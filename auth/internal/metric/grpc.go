@@ -0,0 +1,50 @@
+package metric
+
+import (
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+// StartGRPC учитывает начало gRPC вызова и возвращает функцию, которую нужно
+// вызвать с результатом вызова по его завершении
+func StartGRPC(typ, fullMethod string) func(err error) {
+	if metrics == nil {
+		return func(error) {}
+	}
+
+	service, method := splitMethod(fullMethod)
+	start := time.Now()
+
+	metrics.grpcStarted.WithLabelValues(typ, service, method).Inc()
+	inFlight := metrics.grpcInFlight.WithLabelValues(typ, service, method)
+	inFlight.Inc()
+
+	return func(err error) {
+		inFlight.Dec()
+
+		code := status.Code(err).String()
+		metrics.grpcHandled.WithLabelValues(typ, service, method, code).Inc()
+		metrics.grpcHandlingSec.WithLabelValues(typ, service, method, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// IncGRPCMsgReceived учитывает сообщение, полученное в потоковом вызове
+func IncGRPCMsgReceived(typ, fullMethod string) {
+	if metrics == nil {
+		return
+	}
+
+	service, method := splitMethod(fullMethod)
+	metrics.grpcMsgReceived.WithLabelValues(typ, service, method).Inc()
+}
+
+// IncGRPCMsgSent учитывает сообщение, отправленное в потоковом вызове
+func IncGRPCMsgSent(typ, fullMethod string) {
+	if metrics == nil {
+		return
+	}
+
+	service, method := splitMethod(fullMethod)
+	metrics.grpcMsgSent.WithLabelValues(typ, service, method).Inc()
+}
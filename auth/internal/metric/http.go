@@ -0,0 +1,75 @@
+package metric
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/MercerMorning/go_example/auth/internal/httpstatus"
+)
+
+type routeKey struct{}
+
+// route шаблон маршрута запроса. Уточняется внутри шлюза, поэтому хранится по указателю.
+type route struct {
+	pattern string
+}
+
+// HTTPMiddleware считает HTTP запросы по методу, шаблону маршрута и коду ответа.
+// Шаблон берется из http.ServeMux (/healthz, /admin/log-level), а для запросов шлюза
+// уточняется GatewayMiddleware (/user/v1/{id}). Так путь с идентификаторами не попадает в метки.
+func HTTPMiddleware(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if metrics == nil {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		rt := &route{}
+		_, rt.pattern = mux.Handler(r)
+		if rt.pattern == "" {
+			rt.pattern = "unmatched"
+		}
+
+		rec := httpstatus.NewRecorder(w)
+		start := time.Now()
+
+		metrics.httpInFlight.Inc()
+		defer func() {
+			metrics.httpInFlight.Dec()
+
+			// Middleware стоит внутри recoveryHandler: паника учитывается ответом 500,
+			// который отправит recovery, и передается дальше
+			p := recover()
+			if p != nil {
+				rec.Status = http.StatusInternalServerError
+			}
+
+			code := strconv.Itoa(rec.Status)
+			metrics.httpRequests.WithLabelValues(r.Method, rt.pattern, code).Inc()
+			metrics.httpDurationSec.WithLabelValues(r.Method, rt.pattern, code).Observe(time.Since(start).Seconds())
+
+			if p != nil {
+				panic(p)
+			}
+		}()
+
+		mux.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), routeKey{}, rt)))
+	})
+}
+
+// GatewayMiddleware передает HTTPMiddleware шаблон маршрута, совпавший в шлюзе
+func GatewayMiddleware(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		if rt, ok := r.Context().Value(routeKey{}).(*route); ok {
+			if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+				rt.pattern = pattern.String()
+			}
+		}
+
+		next(w, r, pathParams)
+	}
+}
@@ -1,67 +1,234 @@
 package metric
 
 import (
-	"context"
+	"net/http"
+	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
 )
 
+// Типы gRPC вызовов для метки grpc_type
 const (
-	namespace = "my_space"
-	appName   = "my_app"
+	Unary        = "unary"
+	ClientStream = "client_stream"
+	ServerStream = "server_stream"
+	BidiStream   = "bidi_stream"
 )
 
 type Metrics struct {
-	requestCounter        prometheus.Counter
-	responseCounter       *prometheus.CounterVec
-	histogramResponseTime *prometheus.HistogramVec
+	registry *prometheus.Registry
+
+	grpcStarted     *prometheus.CounterVec
+	grpcHandled     *prometheus.CounterVec
+	grpcHandlingSec *prometheus.HistogramVec
+	grpcInFlight    *prometheus.GaugeVec
+	grpcMsgReceived *prometheus.CounterVec
+	grpcMsgSent     *prometheus.CounterVec
+
+	httpRequests    *prometheus.CounterVec
+	httpDurationSec *prometheus.HistogramVec
+	httpInFlight    prometheus.Gauge
+
+	configReloadCounter   *prometheus.CounterVec
+	configReloadTimestamp prometheus.Gauge
+
+	panicsRecovered *prometheus.CounterVec
 }
 
 var metrics *Metrics
 
-func Init(_ context.Context) error {
-	metrics = &Metrics{
-		requestCounter: promauto.NewCounter(
+// Init создает метрики в отдельном реестре вместе с метриками Go runtime и процесса.
+// До Init функции пакета ничего не делают.
+func Init(cfg config.Metrics) error {
+	grpcLabels := []string{"grpc_type", "grpc_service", "grpc_method"}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		grpcStarted: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: cfg.Namespace,
+				Subsystem: "grpc_server",
+				Name:      "started_total",
+				Help:      "Количество начатых gRPC вызовов",
+			},
+			grpcLabels,
+		),
+		grpcHandled: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: cfg.Namespace,
+				Subsystem: "grpc_server",
+				Name:      "handled_total",
+				Help:      "Количество завершенных gRPC вызовов по коду ответа",
+			},
+			append(grpcLabels, "grpc_code"),
+		),
+		grpcHandlingSec: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: cfg.Namespace,
+				Subsystem: "grpc_server",
+				Name:      "handling_seconds",
+				Help:      "Время обработки gRPC вызова",
+				Buckets:   cfg.Buckets,
+			},
+			append(grpcLabels, "grpc_code"),
+		),
+		grpcInFlight: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: cfg.Namespace,
+				Subsystem: "grpc_server",
+				Name:      "in_flight_requests",
+				Help:      "Количество gRPC вызовов в обработке",
+			},
+			grpcLabels,
+		),
+		grpcMsgReceived: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
-				Namespace: namespace,
-				Subsystem: "grpc",
-				Name:      appName + "_requests_total",
-				Help:      "Количество запросов к серверу",
+				Namespace: cfg.Namespace,
+				Subsystem: "grpc_server",
+				Name:      "msg_received_total",
+				Help:      "Количество полученных сообщений потоковых вызовов",
 			},
+			grpcLabels,
 		),
-		responseCounter: promauto.NewCounterVec(
+		grpcMsgSent: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
-				Namespace: namespace,
-				Subsystem: "grpc",
-				Name:      appName + "_responses_total",
-				Help:      "Количество ответов от сервера",
+				Namespace: cfg.Namespace,
+				Subsystem: "grpc_server",
+				Name:      "msg_sent_total",
+				Help:      "Количество отправленных сообщений потоковых вызовов",
 			},
-			[]string{"status", "method"},
+			grpcLabels,
 		),
-		histogramResponseTime: promauto.NewHistogramVec(
+
+		httpRequests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: cfg.Namespace,
+				Subsystem: "http_server",
+				Name:      "requests_total",
+				Help:      "Количество HTTP запросов по шаблону маршрута и коду ответа",
+			},
+			[]string{"method", "route", "code"},
+		),
+		httpDurationSec: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Namespace: namespace,
-				Subsystem: "grpc",
-				Name:      appName + "_histogram_response_time_seconds",
-				Help:      "Время ответа от сервера",
-				Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 16),
+				Namespace: cfg.Namespace,
+				Subsystem: "http_server",
+				Name:      "request_duration_seconds",
+				Help:      "Время обработки HTTP запроса",
+				Buckets:   cfg.Buckets,
+			},
+			[]string{"method", "route", "code"},
+		),
+		httpInFlight: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: cfg.Namespace,
+				Subsystem: "http_server",
+				Name:      "in_flight_requests",
+				Help:      "Количество HTTP запросов в обработке",
 			},
-			[]string{"status"},
 		),
+
+		configReloadCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: cfg.Namespace,
+				Subsystem: "config",
+				Name:      "reloads_total",
+				Help:      "Количество перезагрузок конфигурации",
+			},
+			[]string{"result"},
+		),
+		configReloadTimestamp: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: cfg.Namespace,
+				Subsystem: "config",
+				Name:      "last_reload_success_timestamp_seconds",
+				Help:      "Время последней успешной перезагрузки конфигурации",
+			},
+		),
+
+		panicsRecovered: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: cfg.Namespace,
+				Name:      "panics_recovered_total",
+				Help:      "Количество паник в обработчиках запросов, перехваченных без остановки сервиса",
+			},
+			[]string{"source"},
+		),
+	}
+
+	err := registerAll(m.registry,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{Namespace: cfg.Namespace}),
+		m.grpcStarted,
+		m.grpcHandled,
+		m.grpcHandlingSec,
+		m.grpcInFlight,
+		m.grpcMsgReceived,
+		m.grpcMsgSent,
+		m.httpRequests,
+		m.httpDurationSec,
+		m.httpInFlight,
+		m.configReloadCounter,
+		m.configReloadTimestamp,
+		m.panicsRecovered,
+	)
+	if err != nil {
+		return err
 	}
 
+	metrics = m
+
 	return nil
 }
 
-func IncRequestCounter() {
-	metrics.requestCounter.Inc()
+func registerAll(registry *prometheus.Registry, cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := registry.Register(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Handler отдает метрики реестра в формате Prometheus
+func Handler() http.Handler {
+	if metrics == nil {
+		return promhttp.Handler()
+	}
+
+	return promhttp.HandlerFor(metrics.registry, promhttp.HandlerOpts{Registry: metrics.registry})
+}
+
+func IncConfigReload(result string) {
+	if metrics == nil {
+		return
+	}
+	metrics.configReloadCounter.WithLabelValues(result).Inc()
+	if result == "success" {
+		metrics.configReloadTimestamp.SetToCurrentTime()
+	}
 }
 
-func IncResponseCounter(status string, method string) {
-	metrics.responseCounter.WithLabelValues(status, method).Inc()
+// IncPanicRecovered учитывает перехваченную панику. source — grpc или http.
+func IncPanicRecovered(source string) {
+	if metrics == nil {
+		return
+	}
+	metrics.panicsRecovered.WithLabelValues(source).Inc()
 }
 
-func HistogramResponseTimeObserve(status string, time float64) {
-	metrics.histogramResponseTime.WithLabelValues(status).Observe(time)
+// splitMethod разбирает полное имя метода /user_v1.UserV1/Create на сервис и метод
+func splitMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+
+	return "unknown", fullMethod
 }
@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/interceptor"
+	"github.com/MercerMorning/go_example/auth/internal/metric"
+)
+
+func TestMetrics(t *testing.T) {
+	cfg := config.Default().Metrics
+	cfg.Namespace = "test"
+	require.NoError(t, metric.Init(cfg))
+
+	_, err := interceptor.MetricsInterceptor(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/user_v1.UserV1/Get"},
+		func(context.Context, interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		})
+	require.Error(t, err)
+
+	gateway := runtime.NewServeMux(runtime.WithMiddlewares(metric.GatewayMiddleware))
+	require.NoError(t, gateway.HandlePath(http.MethodGet, "/user/v1/{id}",
+		func(w http.ResponseWriter, _ *http.Request, _ map[string]string) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	mux.Handle("/", gateway)
+	handler := metric.HTTPMiddleware(mux)
+
+	for _, path := range []string{"/user/v1/1", "/user/v1/2", "/healthz"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	out := scrape(t)
+	require.Contains(t, out, `test_grpc_server_started_total{grpc_method="Get",grpc_service="user_v1.UserV1",grpc_type="unary"} 1`)
+	require.Contains(t, out, `test_grpc_server_handled_total{grpc_code="NotFound",grpc_method="Get",grpc_service="user_v1.UserV1",grpc_type="unary"} 1`)
+	require.Contains(t, out, `test_grpc_server_in_flight_requests{grpc_method="Get",grpc_service="user_v1.UserV1",grpc_type="unary"} 0`)
+	require.Contains(t, out, `test_http_server_requests_total{code="204",method="GET",route="/user/v1/{id=*}"} 2`)
+	require.Contains(t, out, `test_http_server_requests_total{code="200",method="GET",route="/healthz"} 1`)
+	require.Contains(t, out, "test_http_server_request_duration_seconds_bucket")
+	require.Contains(t, out, "go_goroutines")
+	require.Contains(t, out, "test_process_")
+}
+
+func scrape(t *testing.T) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	metric.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
+
+func TestMetricsCountPanics(t *testing.T) {
+	cfg := config.Default().Metrics
+	cfg.Namespace = "test"
+	require.NoError(t, metric.Init(cfg))
+
+	require.PanicsWithValue(t, "nil map", func() {
+		_, _ = interceptor.MetricsInterceptor(context.Background(), nil,
+			&grpc.UnaryServerInfo{FullMethod: "/user_v1.UserV1/Get"},
+			func(context.Context, interface{}) (interface{}, error) {
+				panic("nil map")
+			})
+	})
+	require.PanicsWithValue(t, "nil map", func() {
+		_ = interceptor.StreamMetricsInterceptor(nil, nil,
+			&grpc.StreamServerInfo{FullMethod: "/user_v1.UserV1/Watch", IsServerStream: true},
+			func(interface{}, grpc.ServerStream) error {
+				panic("nil map")
+			})
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("nil map")
+	}))
+	require.PanicsWithValue(t, "nil map", func() {
+		metric.HTTPMiddleware(mux).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	})
+
+	// Паника учитывается так, как ее вернет recovery: Internal и 500, вызов не остается активным
+	out := scrape(t)
+	require.Contains(t, out, `test_grpc_server_handled_total{grpc_code="Internal",grpc_method="Get",grpc_service="user_v1.UserV1",grpc_type="unary"} 1`)
+	require.Contains(t, out, `test_grpc_server_in_flight_requests{grpc_method="Get",grpc_service="user_v1.UserV1",grpc_type="unary"} 0`)
+	require.Contains(t, out, `test_grpc_server_handled_total{grpc_code="Internal",grpc_method="Watch",grpc_service="user_v1.UserV1",grpc_type="server_stream"} 1`)
+	require.Contains(t, out, `test_grpc_server_in_flight_requests{grpc_method="Watch",grpc_service="user_v1.UserV1",grpc_type="server_stream"} 0`)
+	require.Contains(t, out, `test_http_server_requests_total{code="500",method="GET",route="/healthz"} 1`)
+	require.Contains(t, out, "test_http_server_in_flight_requests 0")
+}
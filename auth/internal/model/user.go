@@ -2,9 +2,13 @@ package model
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 )
 
+// ErrUserNotFound возвращается, если пользователь не найден
+var ErrUserNotFound = errors.New("user not found")
+
 type User struct {
 	ID        int64
 	Info      UserInfo
@@ -0,0 +1,265 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/client/db"
+	"github.com/MercerMorning/go_example/auth/internal/httpstatus"
+	"github.com/MercerMorning/go_example/auth/internal/redact"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+)
+
+// Thresholds пороги, после которых операция отправляется как проблема производительности, 0 отключает
+type Thresholds struct {
+	Request time.Duration // unary вызов gRPC
+	Stream  time.Duration // поток gRPC и бизнес-операция
+	Query   time.Duration // запрос к БД
+}
+
+// Middleware - middleware для отправки ошибок и медленных операций HTTP, gRPC и БД в Reporter.
+// С reporter.Nop цепочки обработчиков работают так же, но ничего не отправляют.
+type Middleware struct {
+	reporter   reporter.Reporter
+	thresholds Thresholds
+}
+
+// NewMiddleware создает новый middleware. r = nil, когда отправка отключена.
+func NewMiddleware(r reporter.Reporter, thresholds Thresholds) *Middleware {
+	if r == nil {
+		r = reporter.Nop()
+	}
+
+	return &Middleware{
+		reporter:   r,
+		thresholds: thresholds,
+	}
+}
+
+// HTTPMiddleware создает HTTP middleware: отдельный scope на запрос с данными запроса
+// и транзакция. Паники перехватывает recoveryHandler внутри.
+func (m *Middleware) HTTPMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := m.reporter.NewScope(r.Context(), reporter.Scope{Request: r})
+			ctx, transaction := m.reporter.StartTransaction(ctx, r.Method+" "+r.URL.Path, "http.server")
+
+			rec := httpstatus.NewRecorder(w)
+			defer func() {
+				transaction.SetTag("http.status_code", strconv.Itoa(rec.Status))
+				transaction.Finish(httpError(rec.Status))
+			}()
+
+			next.ServeHTTP(rec, r.WithContext(ctx))
+		})
+	}
+}
+
+// GRPCUnaryInterceptor создает gRPC unary interceptor: отдельный scope и транзакция
+// на вызов, медленные вызовы и ошибки Internal и Unknown отправляются в Reporter
+func (m *Middleware) GRPCUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx = m.withScope(ctx, info.FullMethod)
+
+		// Создаем транзакцию для gRPC запроса
+		ctx, transaction := m.reporter.StartTransaction(ctx, info.FullMethod, "grpc.server")
+		transaction.SetTag("method", info.FullMethod)
+		transaction.SetTag("type", "unary")
+
+		// Выполняем запрос
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		duration := time.Since(start)
+
+		m.capturePerformanceIssue(ctx, info.FullMethod, duration, m.thresholds.Request, map[string]interface{}{
+			"grpc_method": info.FullMethod,
+		})
+
+		transaction.Finish(err)
+		m.captureGRPCError(ctx, err, info.FullMethod, map[string]interface{}{
+			"request": redact.String(req),
+		})
+
+		return resp, err
+	}
+}
+
+// GRPCStreamInterceptor создает gRPC stream interceptor
+func (m *Middleware) GRPCStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx := m.withScope(ss.Context(), info.FullMethod)
+
+		// Создаем транзакцию для gRPC stream
+		ctx, transaction := m.reporter.StartTransaction(ctx, info.FullMethod, "grpc.server")
+		transaction.SetTag("method", info.FullMethod)
+		transaction.SetTag("type", "stream")
+
+		// Выполняем stream
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		duration := time.Since(start)
+
+		m.capturePerformanceIssue(ctx, info.FullMethod, duration, m.thresholds.Stream, map[string]interface{}{
+			"grpc_method": info.FullMethod,
+		})
+
+		transaction.Finish(err)
+		m.captureGRPCError(ctx, err, info.FullMethod, map[string]interface{}{
+			"stream_type": "server",
+		})
+
+		return err
+	}
+}
+
+// DatabaseMiddleware создает хук клиента БД: медленные запросы и ошибки отправляются
+// в Reporter с именем запроса. pgx.ErrNoRows до хука не доходит, отмена запроса не отправляется.
+func (m *Middleware) DatabaseMiddleware() db.QueryHook {
+	return func(ctx context.Context, q db.Query, duration time.Duration, err error) {
+		m.capturePerformanceIssue(ctx, "database_query", duration, m.thresholds.Query, map[string]interface{}{
+			"query": q.Name,
+		})
+
+		if err == nil || errors.Is(err, context.Canceled) {
+			return
+		}
+
+		m.reporter.CaptureError(ctx, err, reporter.Event{
+			Tags: map[string]string{
+				"component": "database",
+				"query":     q.Name,
+			},
+			Extra: map[string]interface{}{
+				"duration": duration.String(),
+			},
+		})
+	}
+}
+
+// BusinessLogicMiddleware создает middleware для бизнес-логики
+func (m *Middleware) BusinessLogicMiddleware(operation string) func(func() error) error {
+	return func(businessOperation func() error) error {
+		start := time.Now()
+		err := businessOperation()
+		duration := time.Since(start)
+
+		// Логируем производительность бизнес-логики
+		m.capturePerformanceIssue(context.Background(), operation, duration, m.thresholds.Stream, map[string]interface{}{
+			"operation": operation,
+		})
+
+		// Логируем ошибки бизнес-логики
+		if err != nil {
+			m.reporter.CaptureError(context.Background(), err, reporter.Event{
+				Tags: map[string]string{
+					"component": "business_logic",
+					"operation": operation,
+				},
+				Extra: map[string]interface{}{
+					"duration": duration.String(),
+				},
+			})
+		}
+
+		return err
+	}
+}
+
+// withScope кладет в контекст отдельный scope вызова, чтобы теги и breadcrumbs
+// одного запроса не попадали в события другого
+func (m *Middleware) withScope(ctx context.Context, method string) context.Context {
+	return m.reporter.NewScope(ctx, reporter.Scope{
+		Tags: map[string]string{"grpc.method": method},
+	})
+}
+
+// capturePerformanceIssue отправляет предупреждение, если операция длилась дольше порога
+func (m *Middleware) capturePerformanceIssue(
+	ctx context.Context,
+	operation string,
+	duration time.Duration,
+	threshold time.Duration,
+	extra map[string]interface{},
+) {
+	if threshold <= 0 || duration <= threshold {
+		return
+	}
+
+	performance := map[string]interface{}{
+		"duration":    duration.String(),
+		"threshold":   threshold.String(),
+		"exceeded_by": (duration - threshold).String(),
+	}
+	for key, value := range extra {
+		performance[key] = value
+	}
+
+	m.reporter.CaptureMessage(ctx, fmt.Sprintf("Performance issue: %s took %v (threshold: %v)",
+		operation, duration, threshold), reporter.Event{
+		Level: reporter.LevelWarning,
+		Tags: map[string]string{
+			"issue_type": "performance",
+			"operation":  operation,
+		},
+		Extra: map[string]interface{}{
+			"performance": performance,
+		},
+	})
+}
+
+// captureGRPCError отправляет только серьезные ошибки: Internal и Unknown.
+// Остальные коды — ожидаемые ответы клиенту.
+func (m *Middleware) captureGRPCError(ctx context.Context, err error, method string, extra map[string]interface{}) {
+	if err == nil {
+		return
+	}
+
+	code := status.Code(err)
+	if code != codes.Internal && code != codes.Unknown {
+		return
+	}
+
+	m.reporter.CaptureError(ctx, err, reporter.Event{
+		Tags: map[string]string{
+			"grpc_method": method,
+			"grpc_code":   code.String(),
+		},
+		Extra: extra,
+	})
+}
+
+// httpError переводит ответ 5xx в ошибку, по которой транзакция получает статус internal_error
+func httpError(code int) error {
+	if code < http.StatusInternalServerError {
+		return nil
+	}
+	return status.Error(codes.Internal, http.StatusText(code))
+}
+
+// serverStream подменяет контекст потока контекстом со scope и транзакцией
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
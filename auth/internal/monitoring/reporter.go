@@ -0,0 +1,67 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+)
+
+// NewReporter создает Reporter по секции error_reporting. Для backend sentry инициализирует
+// SDK по секции sentry (LoadSentryConfig); без DSN ошибки никуда не отправляются.
+// Возвращает функцию, которая отправляет накопленные события и закрывает backend.
+func NewReporter(cfg config.Reporting, sentryCfg config.SentryConfig) (reporter.Reporter, func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	switch cfg.Backend {
+	case "sentry":
+		enterpriseCfg := LoadSentryConfig(sentryCfg)
+		if enterpriseCfg == nil {
+			return reporter.Nop(), noop, nil
+		}
+
+		enterprise := NewSentryEnterprise(enterpriseCfg)
+		if err := enterprise.Init(); err != nil {
+			return nil, nil, fmt.Errorf("failed to init Sentry: %w", err)
+		}
+
+		return reporter.NewSentry(), func(context.Context) error {
+			if !enterprise.Flush() {
+				return errors.New("failed to flush Sentry events in time")
+			}
+			return nil
+		}, nil
+	case "file":
+		file, err := reporter.NewFile(cfg.FilePath)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return file, func(context.Context) error {
+			return file.Close()
+		}, nil
+	case "http":
+		h := reporter.NewHTTP(cfg.URL, cfg.Timeout)
+
+		return h, func(ctx context.Context) error {
+			timeout := cfg.Timeout
+			if deadline, ok := ctx.Deadline(); ok {
+				timeout = time.Until(deadline)
+			}
+
+			if !h.Flush(timeout) {
+				return errors.New("failed to send error reports in time")
+			}
+			return h.Close()
+		}, nil
+	case "memory":
+		return reporter.NewRecorder(), noop, nil
+	case "none":
+		return reporter.Nop(), noop, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown error reporter %q", cfg.Backend)
+	}
+}
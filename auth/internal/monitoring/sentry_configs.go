@@ -2,10 +2,14 @@ package monitoring
 
 import (
 	"os"
-	"strconv"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/getsentry/sentry-go"
+	"google.golang.org/grpc/codes"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
 )
 
 // EnvironmentConfig - конфигурация для разных окружений
@@ -60,85 +64,120 @@ func GetEnvironmentConfig(env string) *EnvironmentConfig {
 	}
 }
 
-// LoadSentryConfigFromEnv загружает конфигурацию Sentry из переменных окружения
-func LoadSentryConfigFromEnv() *SentryEnterpriseConfig {
-	env := getEnv("SENTRY_ENVIRONMENT", "development")
-	envConfig := GetEnvironmentConfig(env)
-
-	dsn := getEnv("SENTRY_DSN", "")
-	if dsn == "" {
-		return nil // Sentry отключен
+// LoadSentryConfig создает настройки SDK по секции sentry. Незаданные max_breadcrumbs
+// и flush_timeout берутся из профиля окружения, server_name — имя хоста.
+// Значения проверены Config.Validate: неверные выражения, коды и теги пропускаются.
+// Возвращает nil, если DSN не задан и Sentry отключен.
+func LoadSentryConfig(base config.SentryConfig) *SentryEnterpriseConfig {
+	if base.DSN == "" {
+		return nil
 	}
 
-	release := getEnv("SENTRY_RELEASE", "unknown")
-	serverName := getEnv("SENTRY_SERVER_NAME", getHostname())
+	envConfig := GetEnvironmentConfig(base.Environment)
+	environment := base.Environment
+	if environment == "" {
+		environment = envConfig.Environment
+	}
 
-	// Загружаем дополнительные настройки из env
-	maxBreadcrumbs := getEnvInt("SENTRY_MAX_BREADCRUMBS", envConfig.MaxBreadcrumbs)
-	maxSpans := getEnvInt("SENTRY_MAX_SPANS", 1000)
-	maxTraceFileSize := getEnvInt64("SENTRY_MAX_TRACE_FILE_SIZE", 10*1024*1024) // 10MB
+	release := base.Release
+	if release == "" {
+		release = "unknown"
+	}
+	serverName := base.ServerName
+	if serverName == "" {
+		serverName = getHostname()
+	}
+	maxBreadcrumbs := base.MaxBreadcrumbs
+	if maxBreadcrumbs == 0 {
+		maxBreadcrumbs = envConfig.MaxBreadcrumbs
+	}
+	flushTimeout := base.FlushTimeout
+	if flushTimeout == 0 {
+		flushTimeout = envConfig.FlushTimeout
+	}
 
-	config := &SentryEnterpriseConfig{
-		DSN:              dsn,
-		Environment:      envConfig.Environment,
+	cfg := &SentryEnterpriseConfig{
+		DSN:              base.DSN,
+		Environment:      environment,
 		Release:          release,
-		Debug:            envConfig.Debug,
-		SampleRate:       envConfig.SampleRate,
-		TracesSampleRate: envConfig.TracesSampleRate,
+		Debug:            base.Debug,
+		SampleRate:       base.SampleRate,
+		TracesSampleRate: base.TracesSampleRate,
 		MaxBreadcrumbs:   maxBreadcrumbs,
-		MaxSpans:         maxSpans,
-		MaxTraceFileSize: maxTraceFileSize,
-		FlushTimeout:     envConfig.FlushTimeout,
+		MaxSpans:         base.MaxSpans,
+		MaxTraceFileSize: 10 * 1024 * 1024, // 10MB
+		FlushTimeout:     flushTimeout,
 		ServerName:       serverName,
-		Dist:             getEnv("SENTRY_DIST", ""),
+		Dist:             base.Dist,
 		Tags: map[string]string{
-			"service":     getEnv("SENTRY_SERVICE", "auth-service"),
+			"service":     base.Service,
 			"version":     release,
-			"environment": envConfig.Environment,
+			"environment": environment,
 			"server":      serverName,
 		},
-		BeforeSend:       createBeforeSendHandler(envConfig.Environment),
-		BeforeBreadcrumb: createBeforeBreadcrumbHandler(envConfig.Environment),
+		BeforeSend:       createBeforeSendHandler(environment),
+		BeforeBreadcrumb: createBeforeBreadcrumbHandler(environment),
+		ScrubFields:      base.ScrubFields,
+		ScrubPatterns:    scrubPatterns(base.ScrubPatterns),
+		DedupWindow:      base.DedupWindow,
+		DedupLimit:       base.DedupLimit,
+		IgnoreGRPCCodes:  grpcCodes(base.IgnoreGRPCCodes),
 	}
 
-	// Добавляем дополнительные теги из env
-	additionalTags := getEnv("SENTRY_ADDITIONAL_TAGS", "")
-	if additionalTags != "" {
-		// Формат: "key1:value1,key2:value2"
-		// Парсим дополнительные теги
-		// TODO: реализовать парсинг
+	for _, tag := range base.AdditionalTags {
+		if key, value, ok := config.SentryTag(tag); ok {
+			cfg.Tags[key] = value
+		}
 	}
 
-	return config
+	return cfg
+}
+
+// scrubPatterns компилирует выражения scrub_patterns
+func scrubPatterns(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if re, err := regexp.Compile(pattern); err == nil {
+			compiled = append(compiled, re)
+		}
+	}
+	return compiled
+}
+
+// grpcCodes переводит имена кодов ignore_grpc_codes в codes.Code
+func grpcCodes(names []string) []codes.Code {
+	result := make([]codes.Code, 0, len(names))
+	for _, name := range names {
+		if code, ok := config.GRPCCode(strings.TrimSpace(name)); ok {
+			result = append(result, code)
+		}
+	}
+	return result
+}
+
+// LoadThresholds возвращает пороги медленных операций Middleware из sentry.slow_threshold
+func LoadThresholds(cfg config.SentrySlowThreshold) Thresholds {
+	return Thresholds{
+		Request: cfg.Request,
+		Stream:  cfg.Stream,
+		Query:   cfg.Query,
+	}
 }
 
 // createBeforeSendHandler создает обработчик BeforeSend для окружения
 func createBeforeSendHandler(environment string) func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
 	return func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
-		// Добавляем информацию о сервере
-		event.ServerName = getHostname()
-		event.Tags["processed_at"] = time.Now().Format(time.RFC3339)
-
 		// Фильтрация в зависимости от окружения
 		switch environment {
 		case "development":
 			// В dev логируем все
 			return event
-		case "staging":
-			// В staging фильтруем debug события
-			if event.Level == sentry.LevelDebug {
-				return nil
-			}
-			return event
-		case "production":
-			// В production агрессивная фильтрация
+		case "staging", "production":
+			// Фильтруем debug события; в production частые ошибки и таймауты
+			// отбрасывает eventFilter по dedup_window и ignore_grpc_codes
 			if event.Level == sentry.LevelDebug {
 				return nil
 			}
-			// Фильтруем частые ошибки
-			if isFrequentError(event) {
-				return nil
-			}
 			return event
 		default:
 			return event
@@ -165,47 +204,6 @@ func createBeforeBreadcrumbHandler(environment string) func(breadcrumb *sentry.B
 	}
 }
 
-// isFrequentError проверяет, является ли ошибка частой
-func isFrequentError(event *sentry.Event) bool {
-	// Простая логика для определения частых ошибок
-	// В реальном проекте это может быть более сложная логика
-	if event.Exception != nil && len(event.Exception) > 0 {
-		exception := event.Exception[0]
-		if exception.Type == "context.DeadlineExceeded" {
-			return true // Игнорируем таймауты
-		}
-	}
-	return false
-}
-
-// getEnv получает переменную окружения с значением по умолчанию
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-// getEnvInt получает int переменную окружения
-func getEnvInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
-}
-
-// getEnvInt64 получает int64 переменную окружения
-func getEnvInt64(key string, defaultValue int64) int64 {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
-}
-
 // getHostname возвращает имя хоста
 func getHostname() string {
 	hostname, err := os.Hostname()
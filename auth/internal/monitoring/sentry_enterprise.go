@@ -3,11 +3,19 @@ package monitoring
 import (
 	"context"
 	"fmt"
-	"os"
+	"math"
+	"math/rand"
+	"regexp"
 	"runtime"
+	"sync/atomic"
 	"time"
 
 	"github.com/getsentry/sentry-go"
+	"google.golang.org/grpc/codes"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
 )
 
 // SentryEnterprise - расширенная конфигурация Sentry для больших проектов
@@ -34,11 +42,39 @@ type SentryEnterpriseConfig struct {
 	Dist         string
 	Tags         map[string]string
 	Integrations []sentry.Integration
+	Transport    sentry.Transport // nil — HTTP транспорт SDK
 
 	// Настройки производительности
 	MaxSpans         int
 	MaxTraceFileSize int64
 	FlushTimeout     time.Duration
+
+	// Фильтрация после BeforeSend: скрытие персональных данных, ограничение повторов
+	// одного fingerprint за DedupWindow и отбрасывание ошибок с кодами IgnoreGRPCCodes
+	ScrubFields     []string
+	ScrubPatterns   []*regexp.Regexp
+	DedupWindow     time.Duration // 0 — без ограничения повторов
+	DedupLimit      int
+	IgnoreGRPCCodes []codes.Code
+}
+
+// Доли событий и транзакций, отправляемых в Sentry. Хранятся как биты float64,
+// чтобы их можно было менять при перезагрузке конфигурации без повторной инициализации SDK.
+var (
+	sampleRate       atomic.Uint64
+	tracesSampleRate atomic.Uint64
+)
+
+// SetSampleRates меняет доли событий и транзакций, отправляемых в Sentry
+func SetSampleRates(events, traces float64) {
+	sampleRate.Store(math.Float64bits(events))
+	tracesSampleRate.Store(math.Float64bits(traces))
+}
+
+// Reload применяет перезагружаемые доли событий и транзакций Sentry
+func Reload(cfg config.Reloadable) error {
+	SetSampleRates(cfg.SentrySampleRate, cfg.SentryTracesSampleRate)
+	return nil
 }
 
 // NewSentryEnterprise создает новую enterprise конфигурацию Sentry
@@ -48,22 +84,42 @@ func NewSentryEnterprise(config *SentryEnterpriseConfig) *SentryEnterprise {
 	}
 }
 
-// Init инициализирует Sentry с enterprise настройками
+// Init инициализирует Sentry с enterprise настройками.
+// Сэмплирование выполняется в BeforeSend и TracesSampler по текущим долям из SetSampleRates,
+// после BeforeSend из конфигурации событие проходит фильтрацию (ScrubFields, DedupWindow, IgnoreGRPCCodes).
 func (se *SentryEnterprise) Init() error {
+	SetSampleRates(se.config.SampleRate, se.config.TracesSampleRate)
+
+	beforeSend := se.config.BeforeSend
+	filter := newEventFilter(se.config)
 	options := sentry.ClientOptions{
-		Dsn:              se.config.DSN,
-		Environment:      se.config.Environment,
-		Release:          se.config.Release,
-		Debug:            se.config.Debug,
-		SampleRate:       se.config.SampleRate,
-		TracesSampleRate: se.config.TracesSampleRate,
-		MaxBreadcrumbs:   se.config.MaxBreadcrumbs,
-		BeforeSend:       se.config.BeforeSend,
-		BeforeBreadcrumb: se.config.BeforeBreadcrumb,
-		ServerName:       se.config.ServerName,
-		Dist:             se.config.Dist,
-		Tags:             se.config.Tags,
-		MaxSpans:         se.config.MaxSpans,
+		Dsn:         se.config.DSN,
+		Environment: se.config.Environment,
+		Release:     se.config.Release,
+		Debug:       se.config.Debug,
+		SampleRate:  1.0,
+		TracesSampler: func(_ sentry.SamplingContext) float64 {
+			return math.Float64frombits(tracesSampleRate.Load())
+		},
+		MaxBreadcrumbs: se.config.MaxBreadcrumbs,
+		BeforeSend: func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+			if rand.Float64() >= math.Float64frombits(sampleRate.Load()) {
+				return nil
+			}
+			if beforeSend != nil {
+				if event = beforeSend(event, hint); event == nil {
+					return nil
+				}
+			}
+			return filter.event(event, hint)
+		},
+		BeforeSendTransaction: filter.transaction,
+		BeforeBreadcrumb:      se.config.BeforeBreadcrumb,
+		ServerName:            se.config.ServerName,
+		Dist:                  se.config.Dist,
+		Tags:                  se.config.Tags,
+		MaxSpans:              se.config.MaxSpans,
+		Transport:             se.config.Transport,
 	}
 
 	return sentry.Init(options)
@@ -77,13 +133,12 @@ func (se *SentryEnterprise) CaptureErrorWithContext(
 	extra map[string]interface{},
 	user *sentry.User,
 ) {
-	sentry.WithScope(func(scope *sentry.Scope) {
+	hub := reporter.SentryHub(ctx)
+	hub.WithScope(func(scope *sentry.Scope) {
 		// Устанавливаем контекст
-		scope.SetContext("request", map[string]interface{}{
-			"method":  getContextValue(ctx, "method"),
-			"url":     getContextValue(ctx, "url"),
-			"headers": getContextValue(ctx, "headers"),
-		})
+		if id, ok := requestid.FromContext(ctx); ok {
+			scope.SetTag("request_id", id)
+		}
 
 		// Устанавливаем пользователя
 		if user != nil {
@@ -108,56 +163,64 @@ func (se *SentryEnterprise) CaptureErrorWithContext(
 			"goroutines": runtime.NumGoroutine(),
 		})
 
-		sentry.CaptureException(err)
+		hub.CaptureException(err)
 	})
 }
 
-// CapturePerformanceIssue отправляет информацию о проблемах производительности
+// CapturePerformanceIssue отправляет информацию о проблемах производительности через Reporter по умолчанию
 func (se *SentryEnterprise) CapturePerformanceIssue(
+	ctx context.Context,
 	operation string,
 	duration time.Duration,
 	threshold time.Duration,
-	context map[string]interface{},
+	extra map[string]interface{},
 ) {
-	if duration > threshold {
-		sentry.WithScope(func(scope *sentry.Scope) {
-			scope.SetLevel(sentry.LevelWarning)
-			scope.SetTag("issue_type", "performance")
-			scope.SetTag("operation", operation)
-			scope.SetContext("performance", map[string]interface{}{
+	if threshold <= 0 || duration <= threshold {
+		return
+	}
+
+	event := reporter.Event{
+		Level: reporter.LevelWarning,
+		Tags: map[string]string{
+			"issue_type": "performance",
+			"operation":  operation,
+		},
+		Extra: map[string]interface{}{
+			"performance": map[string]interface{}{
 				"duration":    duration.String(),
 				"threshold":   threshold.String(),
 				"exceeded_by": (duration - threshold).String(),
-			})
-
-			for key, value := range context {
-				scope.SetContext(key, map[string]interface{}{"value": value})
-			}
-
-			sentry.CaptureMessage(fmt.Sprintf("Performance issue: %s took %v (threshold: %v)",
-				operation, duration, threshold))
-		})
+			},
+		},
 	}
+	for key, value := range extra {
+		event.Extra[key] = value
+	}
+
+	reporter.CaptureMessage(ctx, fmt.Sprintf("Performance issue: %s took %v (threshold: %v)",
+		operation, duration, threshold), event)
 }
 
-// CaptureBusinessEvent отправляет бизнес-события
+// CaptureBusinessEvent отправляет бизнес-события через Reporter по умолчанию
 func (se *SentryEnterprise) CaptureBusinessEvent(
+	ctx context.Context,
 	eventType string,
 	userID string,
 	properties map[string]interface{},
 ) {
-	sentry.WithScope(func(scope *sentry.Scope) {
-		scope.SetLevel(sentry.LevelInfo)
-		scope.SetTag("event_type", "business")
-		scope.SetTag("business_event", eventType)
-		scope.SetUser(sentry.User{ID: userID})
-
-		scope.SetContext("business_event", map[string]interface{}{
-			"type":       eventType,
-			"properties": properties,
-		})
-
-		sentry.CaptureMessage(fmt.Sprintf("Business event: %s", eventType))
+	reporter.CaptureMessage(ctx, fmt.Sprintf("Business event: %s", eventType), reporter.Event{
+		Level: reporter.LevelInfo,
+		Tags: map[string]string{
+			"event_type":     "business",
+			"business_event": eventType,
+		},
+		Extra: map[string]interface{}{
+			"business_event": map[string]interface{}{
+				"type":       eventType,
+				"user_id":    userID,
+				"properties": properties,
+			},
+		},
 	})
 }
 
@@ -168,7 +231,7 @@ func (se *SentryEnterprise) StartTransactionWithContext(
 	operation string,
 	tags map[string]string,
 ) *sentry.Span {
-	transaction := sentry.StartTransaction(ctx, name)
+	transaction := sentry.StartTransaction(ctx, name, sentry.WithOpName(operation))
 
 	// Добавляем теги к транзакции
 	for key, value := range tags {
@@ -185,7 +248,7 @@ func (se *SentryEnterprise) CreateSpan(
 	description string,
 	tags map[string]string,
 ) *sentry.Span {
-	span := parent.StartChild(operation)
+	span := parent.StartChild(operation, sentry.WithDescription(description))
 
 	// Добавляем теги к span
 	for key, value := range tags {
@@ -200,16 +263,10 @@ func (se *SentryEnterprise) Flush() bool {
 	return sentry.Flush(se.config.FlushTimeout)
 }
 
-// getContextValue извлекает значение из контекста
-func getContextValue(ctx context.Context, key string) interface{} {
-	if value := ctx.Value(key); value != nil {
-		return value
-	}
-	return nil
-}
-
 // DefaultEnterpriseConfig возвращает конфигурацию по умолчанию для enterprise
 func DefaultEnterpriseConfig(dsn, environment, release string) *SentryEnterpriseConfig {
+	defaults := config.Default().Sentry
+
 	return &SentryEnterpriseConfig{
 		DSN:              dsn,
 		Environment:      environment,
@@ -232,8 +289,7 @@ func DefaultEnterpriseConfig(dsn, environment, release string) *SentryEnterprise
 			}
 
 			// Добавляем дополнительную информацию
-			event.ServerName = getServerName()
-			event.Tags["processed_at"] = time.Now().Format(time.RFC3339)
+			event.ServerName = getHostname()
 
 			return event
 		},
@@ -244,14 +300,9 @@ func DefaultEnterpriseConfig(dsn, environment, release string) *SentryEnterprise
 			}
 			return breadcrumb
 		},
+		ScrubFields:     defaults.ScrubFields,
+		DedupWindow:     defaults.DedupWindow,
+		DedupLimit:      defaults.DedupLimit,
+		IgnoreGRPCCodes: grpcCodes(defaults.IgnoreGRPCCodes),
 	}
 }
-
-// getServerName возвращает имя сервера
-func getServerName() string {
-	hostname, err := os.Hostname()
-	if err != nil {
-		return "unknown"
-	}
-	return hostname
-}
@@ -0,0 +1,345 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/redact"
+)
+
+// maxFingerprints сколько fingerprint помнит ограничитель повторов
+const maxFingerprints = 10000
+
+// Значения, которые скрываются в любых текстах события: email, учетные данные
+// схем Bearer и Basic и JWT
+var defaultScrubPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	regexp.MustCompile(`(?i)\b(?:bearer|basic)\s+[A-Za-z0-9._~+/=-]+`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+}
+
+// Заголовки запроса, которые скрываются независимо от ScrubFields
+var sensitiveHeaders = map[string]struct{}{
+	"authorization":       {},
+	"proxy-authorization": {},
+	"cookie":              {},
+	"set-cookie":          {},
+	"x-api-key":           {},
+}
+
+// eventFilter последний шаг BeforeSend: отбрасывает ошибки с игнорируемыми кодами gRPC
+// и повторы одного fingerprint сверх лимита, затем удаляет из события персональные данные
+type eventFilter struct {
+	scrubber *scrubber
+	ignore   map[codes.Code]struct{}
+	limiter  *fingerprintLimiter // nil — без ограничения
+}
+
+func newEventFilter(cfg *SentryEnterpriseConfig) *eventFilter {
+	f := &eventFilter{
+		scrubber: newScrubber(cfg.ScrubFields, cfg.ScrubPatterns),
+		ignore:   make(map[codes.Code]struct{}, len(cfg.IgnoreGRPCCodes)),
+	}
+	for _, code := range cfg.IgnoreGRPCCodes {
+		f.ignore[code] = struct{}{}
+	}
+	if cfg.DedupWindow > 0 && cfg.DedupLimit > 0 {
+		f.limiter = newFingerprintLimiter(cfg.DedupWindow, cfg.DedupLimit)
+	}
+
+	return f
+}
+
+// event фильтрует событие ошибки или сообщения, nil — событие не отправляется
+func (f *eventFilter) event(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+	if code, ok := eventCode(event, hint); ok {
+		if _, ignored := f.ignore[code]; ignored {
+			return nil
+		}
+	}
+	if f.limiter != nil && !f.limiter.allow(fingerprint(event), time.Now()) {
+		return nil
+	}
+
+	f.scrubber.event(event)
+	return event
+}
+
+// transaction удаляет персональные данные из транзакции. Транзакции не ограничиваются:
+// их количество регулирует traces_sample_rate.
+func (f *eventFilter) transaction(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+	f.scrubber.event(event)
+	return event
+}
+
+// eventCode определяет код gRPC ошибки события: по исходной ошибке, а для событий
+// без нее — по тегу grpc_code, который ставит Middleware
+func eventCode(event *sentry.Event, hint *sentry.EventHint) (codes.Code, bool) {
+	if hint != nil && hint.OriginalException != nil {
+		err := hint.OriginalException
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			return codes.DeadlineExceeded, true
+		case errors.Is(err, context.Canceled):
+			return codes.Canceled, true
+		}
+		if s, ok := status.FromError(err); ok {
+			return s.Code(), true
+		}
+	}
+
+	return config.GRPCCode(event.Tags["grpc_code"])
+}
+
+// fingerprint возвращает ключ одинаковых событий: заданный fingerprint или сообщение
+// вместе с типами и текстами исключений
+func fingerprint(event *sentry.Event) uint64 {
+	h := fnv.New64a()
+	if len(event.Fingerprint) > 0 {
+		for _, part := range event.Fingerprint {
+			h.Write([]byte(part))
+			h.Write([]byte{0})
+		}
+		return h.Sum64()
+	}
+
+	h.Write([]byte(event.Message))
+	for _, exception := range event.Exception {
+		h.Write([]byte{0})
+		h.Write([]byte(exception.Type))
+		h.Write([]byte{0})
+		h.Write([]byte(exception.Value))
+	}
+	return h.Sum64()
+}
+
+// fingerprintLimiter пропускает не больше limit событий с одним fingerprint за окно window
+type fingerprintLimiter struct {
+	window time.Duration
+	limit  int
+
+	mu   sync.Mutex
+	seen map[uint64]*fingerprintWindow
+}
+
+type fingerprintWindow struct {
+	start time.Time
+	count int
+}
+
+func newFingerprintLimiter(window time.Duration, limit int) *fingerprintLimiter {
+	return &fingerprintLimiter{
+		window: window,
+		limit:  limit,
+		seen:   make(map[uint64]*fingerprintWindow),
+	}
+}
+
+func (l *fingerprintLimiter) allow(key uint64, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.seen[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		if !ok && len(l.seen) >= maxFingerprints {
+			l.evict(now)
+		}
+		l.seen[key] = &fingerprintWindow{start: now, count: 1}
+		return true
+	}
+
+	w.count++
+	return w.count <= l.limit
+}
+
+// evict удаляет закончившиеся окна. Если все окна еще открыты, ограничитель
+// начинает счет заново, чтобы поток разных ошибок не занимал память без предела.
+func (l *fingerprintLimiter) evict(now time.Time) {
+	for key, w := range l.seen {
+		if now.Sub(w.start) >= l.window {
+			delete(l.seen, key)
+		}
+	}
+	if len(l.seen) >= maxFingerprints {
+		clear(l.seen)
+	}
+}
+
+// scrubber скрывает значения ключей из fields и совпадения patterns в текстах события.
+// Карты и breadcrumbs события общие со scope hub, поэтому изменяются их копии.
+type scrubber struct {
+	fields   []string
+	patterns []*regexp.Regexp
+	assign   *regexp.Regexp // key=value и "key": "value" для ключей из fields
+}
+
+func newScrubber(fields []string, patterns []*regexp.Regexp) *scrubber {
+	s := &scrubber{
+		patterns: append(append([]*regexp.Regexp{}, defaultScrubPatterns...), patterns...),
+	}
+
+	quoted := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+		s.fields = append(s.fields, field)
+		quoted = append(quoted, regexp.QuoteMeta(field))
+	}
+	if len(quoted) > 0 {
+		s.assign = regexp.MustCompile(`(?i)([\w.-]*(?:` + strings.Join(quoted, "|") +
+			`)[\w.-]*["']?\s*[:=]\s*["']?)[^\s&"',;}]+`)
+	}
+
+	return s
+}
+
+func (s *scrubber) event(event *sentry.Event) {
+	event.Message = s.string(event.Message)
+	for i := range event.Exception {
+		event.Exception[i].Value = s.string(event.Exception[i].Value)
+	}
+
+	event.Tags = s.stringMap(event.Tags)
+	event.Extra = s.valueMap(event.Extra)
+	if event.Contexts != nil {
+		contexts := make(map[string]sentry.Context, len(event.Contexts))
+		for key, value := range event.Contexts {
+			contexts[key] = s.valueMap(value)
+		}
+		event.Contexts = contexts
+	}
+
+	for i, breadcrumb := range event.Breadcrumbs {
+		scrubbed := *breadcrumb
+		scrubbed.Message = s.string(scrubbed.Message)
+		scrubbed.Data = s.valueMap(scrubbed.Data)
+		event.Breadcrumbs[i] = &scrubbed
+	}
+
+	if event.Request != nil {
+		request := *event.Request
+		request.URL = s.string(request.URL)
+		request.QueryString = s.string(request.QueryString)
+		request.Data = s.string(request.Data)
+		if request.Cookies != "" {
+			request.Cookies = redact.Mask
+		}
+		request.Headers = s.headers(request.Headers)
+		request.Env = s.stringMap(request.Env)
+		event.Request = &request
+	}
+
+	event.User.Email = ""
+	event.User.Data = s.stringMap(event.User.Data)
+}
+
+// key сообщает, что значение ключа нужно скрыть целиком
+func (s *scrubber) key(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range s.fields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *scrubber) string(value string) string {
+	if value == "" {
+		return value
+	}
+	if s.assign != nil {
+		value = s.assign.ReplaceAllString(value, "${1}"+redact.Mask)
+	}
+	for _, pattern := range s.patterns {
+		value = pattern.ReplaceAllString(value, redact.Mask)
+	}
+	return value
+}
+
+func (s *scrubber) headers(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+
+	scrubbed := make(map[string]string, len(headers))
+	for key, value := range headers {
+		if _, ok := sensitiveHeaders[strings.ToLower(key)]; ok || s.key(key) {
+			scrubbed[key] = redact.Mask
+			continue
+		}
+		scrubbed[key] = s.string(value)
+	}
+	return scrubbed
+}
+
+func (s *scrubber) stringMap(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+
+	scrubbed := make(map[string]string, len(values))
+	for key, value := range values {
+		if s.key(key) {
+			scrubbed[key] = redact.Mask
+			continue
+		}
+		scrubbed[key] = s.string(value)
+	}
+	return scrubbed
+}
+
+func (s *scrubber) valueMap(values map[string]interface{}) map[string]interface{} {
+	if values == nil {
+		return nil
+	}
+
+	scrubbed := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if s.key(key) {
+			scrubbed[key] = redact.Mask
+			continue
+		}
+		scrubbed[key] = s.value(value)
+	}
+	return scrubbed
+}
+
+func (s *scrubber) value(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return s.string(v)
+	case error:
+		return s.string(v.Error())
+	case map[string]interface{}:
+		return s.valueMap(v)
+	case map[string]string:
+		return s.stringMap(v)
+	case []interface{}:
+		scrubbed := make([]interface{}, len(v))
+		for i, item := range v {
+			scrubbed[i] = s.value(item)
+		}
+		return scrubbed
+	case []string:
+		scrubbed := make([]string, len(v))
+		for i, item := range v {
+			scrubbed[i] = s.string(item)
+		}
+		return scrubbed
+	default:
+		return value
+	}
+}
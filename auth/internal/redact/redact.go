@@ -0,0 +1,178 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/pkg/options"
+)
+
+// Mask значение, которым заменяются чувствительные строки
+const Mask = "[REDACTED]"
+
+var global atomic.Pointer[Redactor]
+
+func init() {
+	global.Store(New(config.Default().Redaction))
+}
+
+// Redactor скрывает чувствительные поля protobuf сообщений и ограничивает размер
+// значений, которые попадают в логи, теги span и контекст Sentry
+type Redactor struct {
+	fields  map[string]struct{}
+	maxSize int
+}
+
+// New создает Redactor. Поле скрывается, если у него есть опция (options.sensitive)
+// или его короткое либо полное имя указано в cfg.Fields.
+func New(cfg config.Redaction) *Redactor {
+	r := &Redactor{
+		fields:  make(map[string]struct{}, len(cfg.Fields)),
+		maxSize: cfg.MaxSize,
+	}
+	for _, f := range cfg.Fields {
+		r.fields[f] = struct{}{}
+	}
+
+	return r
+}
+
+// Init задает Redactor, который используют функции пакета
+func Init(cfg config.Redaction) {
+	global.Store(New(cfg))
+}
+
+// String возвращает значение в виде JSON со скрытыми полями, обрезанное до максимального размера
+func String(v interface{}) string {
+	return global.Load().String(v)
+}
+
+// Value подготавливает значение для контекста Sentry: protobuf сообщения
+// заменяются строкой со скрытыми полями, остальные значения возвращаются как есть
+func Value(v interface{}) interface{} {
+	if _, ok := v.(proto.Message); ok {
+		return String(v)
+	}
+	return v
+}
+
+// Message возвращает копию сообщения со скрытыми полями
+func (r *Redactor) Message(msg proto.Message) proto.Message {
+	clone := proto.Clone(msg)
+	r.redact(clone.ProtoReflect())
+
+	return clone
+}
+
+// String возвращает значение в виде JSON со скрытыми полями, обрезанное до максимального размера
+func (r *Redactor) String(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	var (
+		raw []byte
+		err error
+	)
+	if msg, ok := v.(proto.Message); ok {
+		raw, err = protojson.MarshalOptions{UseProtoNames: true}.Marshal(r.Message(msg))
+	} else {
+		raw, err = json.Marshal(v)
+	}
+	if err != nil {
+		return r.truncate(fmt.Sprintf("%+v", v))
+	}
+
+	// protojson намеренно делает вывод нестабильным, приводим его к компактному виду
+	var compact bytes.Buffer
+	if json.Compact(&compact, raw) == nil {
+		raw = compact.Bytes()
+	}
+
+	return r.truncate(string(raw))
+}
+
+func (r *Redactor) redact(m protoreflect.Message) {
+	var sensitive []protoreflect.FieldDescriptor
+
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if r.sensitive(fd) {
+			sensitive = append(sensitive, fd)
+			return true
+		}
+
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				r.redact(list.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				r.redact(mv.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			r.redact(v.Message())
+		}
+
+		return true
+	})
+
+	// Изменяем сообщение после обхода: менять поля во время Range нельзя
+	for _, fd := range sensitive {
+		mask(m, fd)
+	}
+}
+
+func (r *Redactor) sensitive(fd protoreflect.FieldDescriptor) bool {
+	if _, ok := r.fields[string(fd.Name())]; ok {
+		return true
+	}
+	if _, ok := r.fields[string(fd.FullName())]; ok {
+		return true
+	}
+
+	marked, _ := proto.GetExtension(fd.Options(), options.E_Sensitive).(bool)
+	return marked
+}
+
+// mask заменяет строки маской, остальные значения очищает
+func mask(m protoreflect.Message, fd protoreflect.FieldDescriptor) {
+	switch {
+	case fd.IsList() && fd.Kind() == protoreflect.StringKind:
+		list := m.Mutable(fd).List()
+		for i := 0; i < list.Len(); i++ {
+			list.Set(i, protoreflect.ValueOfString(Mask))
+		}
+	case fd.IsList() || fd.IsMap():
+		m.Clear(fd)
+	case fd.Kind() == protoreflect.StringKind:
+		m.Set(fd, protoreflect.ValueOfString(Mask))
+	case fd.Kind() == protoreflect.BytesKind:
+		m.Set(fd, protoreflect.ValueOfBytes([]byte(Mask)))
+	default:
+		m.Clear(fd)
+	}
+}
+
+func (r *Redactor) truncate(s string) string {
+	if r.maxSize <= 0 || len(s) <= r.maxSize {
+		return s
+	}
+
+	cut := r.maxSize
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+
+	return fmt.Sprintf("%s...(truncated %d bytes)", s[:cut], len(s)-cut)
+}
@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/redact"
+	desc "github.com/MercerMorning/go_example/auth/pkg/user_v1"
+)
+
+func TestRedactorString(t *testing.T) {
+	t.Parallel()
+
+	req := &desc.CreateRequest{
+		Name:            "Alice",
+		Email:           "alice@example.com",
+		Password:        "secret-password",
+		PasswordConfirm: "secret-password",
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config.Redaction
+		value    interface{}
+		expected string
+	}{
+		{
+			name:     "sensitive option",
+			cfg:      config.Redaction{MaxSize: 1024},
+			value:    req,
+			expected: `{"name":"Alice","email":"alice@example.com","password":"[REDACTED]","password_confirm":"[REDACTED]"}`,
+		},
+		{
+			name:     "configured short and full names",
+			cfg:      config.Redaction{MaxSize: 1024, Fields: []string{"email", "user_v1.UpdateRequest.name"}},
+			value:    &desc.UpdateRequest{Id: 1, Name: wrapperspb.String("Alice"), Email: wrapperspb.String("a@example.com")},
+			expected: `{"id":"1"}`,
+		},
+		{
+			name:     "not a proto message",
+			cfg:      config.Redaction{MaxSize: 1024},
+			value:    map[string]int{"a": 1},
+			expected: `{"a":1}`,
+		},
+		{
+			name:     "truncation",
+			cfg:      config.Redaction{MaxSize: 10},
+			value:    strings.Repeat("x", 20),
+			expected: `"xxxxxxxxx...(truncated 12 bytes)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, redact.New(tt.cfg).String(tt.value))
+		})
+	}
+
+	// Исходное сообщение не меняется
+	require.Equal(t, "secret-password", req.GetPassword())
+}
@@ -0,0 +1,167 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/metric"
+)
+
+// Subscriber применяет перезагружаемое подмножество конфигурации
+type Subscriber func(cfg config.Reloadable) error
+
+type subscriber struct {
+	name  string
+	apply Subscriber
+}
+
+// Reloader перечитывает конфигурацию по SIGHUP или при изменении файлов,
+// валидирует ее и атомарно заменяет перезагружаемое подмножество.
+// Остальные настройки применяются только после перезапуска.
+type Reloader struct {
+	loader  *config.Loader
+	current atomic.Pointer[config.Config]
+
+	mu          sync.Mutex
+	subscribers []subscriber
+}
+
+// New создает Reloader с уже загруженной конфигурацией
+func New(loader *config.Loader, cfg *config.Config) *Reloader {
+	r := &Reloader{loader: loader}
+	r.current.Store(cfg)
+
+	return r
+}
+
+// Current возвращает действующую конфигурацию
+func (r *Reloader) Current() *config.Config {
+	return r.current.Load()
+}
+
+// Subscribe регистрирует подписчика и сразу применяет к нему текущую конфигурацию
+func (r *Reloader) Subscribe(name string, apply Subscriber) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := apply(r.current.Load().Reloadable()); err != nil {
+		return fmt.Errorf("failed to apply config to %s: %w", name, err)
+	}
+	r.subscribers = append(r.subscribers, subscriber{name: name, apply: apply})
+
+	return nil
+}
+
+// Reload перечитывает конфигурацию и уведомляет подписчиков.
+// Невалидная конфигурация отклоняется целиком, действующая остается без изменений.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.reload()
+	if err != nil {
+		metric.IncConfigReload("failure")
+		logger.Error("Failed to reload config", zap.Error(err))
+		return err
+	}
+
+	metric.IncConfigReload("success")
+	logger.Info("Config reloaded")
+
+	return nil
+}
+
+func (r *Reloader) reload() error {
+	loaded, err := r.loader.Load()
+	if err != nil {
+		return err
+	}
+
+	current := r.current.Load()
+	if !reflect.DeepEqual(loaded.WithReloadable(current.Reloadable()), current) {
+		logger.Warn("Config contains changes that are applied only after restart")
+	}
+
+	next := current.WithReloadable(loaded.Reloadable())
+
+	for i, s := range r.subscribers {
+		if err = s.apply(next.Reloadable()); err != nil {
+			return errors.Join(fmt.Errorf("%s: %w", s.name, err), r.rollback(r.subscribers[:i], current))
+		}
+	}
+	r.current.Store(next)
+
+	return nil
+}
+
+// rollback возвращает подписчикам, уже получившим новую конфигурацию, действующую,
+// чтобы отклоненная конфигурация не применялась частично
+func (r *Reloader) rollback(applied []subscriber, current *config.Config) error {
+	var errs []error
+	for _, s := range applied {
+		if err := s.apply(current.Reloadable()); err != nil {
+			errs = append(errs, fmt.Errorf("rollback %s: %w", s.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run перезагружает конфигурацию по SIGHUP и при изменении файлов конфигурации
+// (проверяются раз в interval, 0 отключает проверку). Блокируется до отмены ctx.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	modTimes := r.modTimes()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			logger.Info("Received SIGHUP, reloading config")
+			_ = r.Reload()
+			modTimes = r.modTimes()
+		case <-tick:
+			latest := r.modTimes()
+			if reflect.DeepEqual(latest, modTimes) {
+				continue
+			}
+			modTimes = latest
+
+			logger.Info("Config files changed, reloading config")
+			_ = r.Reload()
+		}
+	}
+}
+
+func (r *Reloader) modTimes() map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	for _, file := range r.loader.Files() {
+		if info, err := os.Stat(file); err == nil {
+			modTimes[file] = info.ModTime()
+		}
+	}
+
+	return modTimes
+}
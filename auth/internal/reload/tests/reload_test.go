@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/reload"
+)
+
+const baseConfig = `
+pg:
+  dsn: "host=localhost dbname=auth"
+logger:
+  level: info
+rate_limit:
+  rps: 10
+  burst: 5
+`
+
+func TestReload(t *testing.T) {
+	logger.Init(zapcore.NewNopCore())
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configPath, baseConfig)
+	t.Setenv("CONFIG_PATH", configPath)
+
+	loader := config.NewLoader(nil)
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	var applied []config.Reloadable
+	reloader := reload.New(loader, cfg)
+	require.NoError(t, reloader.Subscribe("test", func(cfg config.Reloadable) error {
+		applied = append(applied, cfg)
+		return nil
+	}))
+	require.Len(t, applied, 1)
+	require.Equal(t, "info", applied[0].LogLevel)
+
+	// Меняются и перезагружаемые, и статические настройки: применяются только первые
+	writeFile(t, configPath, baseConfig+`
+cors:
+  allowed_origins: ["https://example.com"]
+grpc:
+  port: "60000"
+`)
+	require.NoError(t, reloader.Reload())
+	require.Len(t, applied, 2)
+	require.Equal(t, []string{"https://example.com"}, applied[1].CORS.AllowedOrigins)
+	require.Equal(t, []string{"https://example.com"}, reloader.Current().CORS.AllowedOrigins)
+	require.Equal(t, "50051", reloader.Current().GRPC.Port)
+
+	// Невалидная конфигурация отклоняется, действующая сохраняется
+	writeFile(t, configPath, strings.Replace(baseConfig, "rps: 10", "rps: -1", 1))
+	require.ErrorContains(t, reloader.Reload(), "rate_limit.rps")
+	require.Len(t, applied, 2)
+	require.Equal(t, 10.0, reloader.Current().RateLimit.RPS)
+}
+
+func TestReloadRollsBackRejectedConfig(t *testing.T) {
+	logger.Init(zapcore.NewNopCore())
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, configPath, baseConfig)
+	t.Setenv("CONFIG_PATH", configPath)
+
+	loader := config.NewLoader(nil)
+	cfg, err := loader.Load()
+	require.NoError(t, err)
+
+	var applied []config.Reloadable
+	reloader := reload.New(loader, cfg)
+	require.NoError(t, reloader.Subscribe("logger", func(cfg config.Reloadable) error {
+		applied = append(applied, cfg)
+		return nil
+	}))
+	require.NoError(t, reloader.Subscribe("cors", func(cfg config.Reloadable) error {
+		if slices.Contains(cfg.CORS.AllowedOrigins, "https://example.com") {
+			return errors.New("origins are not supported")
+		}
+		return nil
+	}))
+
+	// Второй подписчик отклоняет конфигурацию: первый получает действующую обратно
+	writeFile(t, configPath, strings.Replace(baseConfig, "level: info", "level: debug", 1)+`
+cors:
+  allowed_origins: ["https://example.com"]
+`)
+	require.ErrorContains(t, reloader.Reload(), "cors: origins are not supported")
+	require.Len(t, applied, 3)
+	require.Equal(t, "debug", applied[1].LogLevel)
+	require.Equal(t, "info", applied[2].LogLevel)
+	require.Equal(t, "info", reloader.Current().Logger.Level)
+	require.NotContains(t, reloader.Current().CORS.AllowedOrigins, "https://example.com")
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
@@ -0,0 +1,61 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// File записывает события в файл по одному JSON объекту (Record) на строку.
+// Используется там, где Sentry недоступен: локально и в офлайн окружениях.
+type File struct {
+	local
+
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewFile открывает файл для дозаписи, создавая его каталог
+func NewFile(path string) (*File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create error reports directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open error reports file: %w", err)
+	}
+
+	f := &File{file: file, enc: json.NewEncoder(file)}
+	f.local = local{write: f.write}
+
+	return f, nil
+}
+
+func (f *File) write(rec Record) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Ошибку записи вернуть некуда: методы Reporter не возвращают ошибок
+	_ = f.enc.Encode(rec)
+}
+
+// Flush сбрасывает файл на диск
+func (f *File) Flush(time.Duration) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Sync() == nil
+}
+
+// Close закрывает файл
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Close()
+}
@@ -0,0 +1,135 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// httpQueueSize сколько записей ждут отправки, остальные отбрасываются
+const httpQueueSize = 1000
+
+// HTTP отправляет события POST запросом с JSON телом (Record) на адрес, например
+// на локальный сборщик вместо Sentry. Запросы выполняются в фоне по одному, чтобы не
+// задерживать обработчики; при переполненной очереди записи отбрасываются.
+type HTTP struct {
+	local
+
+	url    string
+	client *http.Client
+	queue  chan httpItem
+	done   chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// httpItem запись для отправки или, если flushed не nil, отметка для Flush
+type httpItem struct {
+	rec     Record
+	flushed chan struct{}
+}
+
+// NewHTTP запускает отправку на url с таймаутом одного запроса
+func NewHTTP(url string, timeout time.Duration) *HTTP {
+	h := &HTTP{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+		queue:  make(chan httpItem, httpQueueSize),
+		done:   make(chan struct{}),
+	}
+	h.local = local{write: h.write}
+
+	go h.run()
+
+	return h
+}
+
+func (h *HTTP) write(rec Record) {
+	h.enqueue(httpItem{rec: rec}, nil)
+}
+
+// enqueue ставит запись в очередь. Без timeout запись отбрасывается, если очередь заполнена.
+func (h *HTTP) enqueue(item httpItem, timeout <-chan time.Time) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return false
+	}
+
+	if timeout == nil {
+		select {
+		case h.queue <- item:
+			return true
+		default:
+			return false
+		}
+	}
+
+	select {
+	case h.queue <- item:
+		return true
+	case <-timeout:
+		return false
+	}
+}
+
+func (h *HTTP) run() {
+	defer close(h.done)
+
+	for item := range h.queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		h.send(item.rec)
+	}
+}
+
+func (h *HTTP) send(rec Record) {
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+
+	resp, err := h.client.Post(h.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	_ = resp.Body.Close()
+}
+
+// Flush ждет, пока будут отправлены записи, поставленные в очередь до вызова
+func (h *HTTP) Flush(timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	flushed := make(chan struct{})
+	if !h.enqueue(httpItem{flushed: flushed}, timer.C) {
+		return false
+	}
+
+	select {
+	case <-flushed:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// Close отправляет оставшиеся записи и останавливает отправку. После Close события отбрасываются.
+func (h *HTTP) Close() error {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.queue)
+	}
+	h.mu.Unlock()
+
+	<-h.done
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MercerMorning/go_example/auth/internal/redact"
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+)
+
+// Kind вид записи локального backend'а
+type Kind string
+
+const (
+	KindError       Kind = "error"
+	KindMessage     Kind = "message"
+	KindBreadcrumb  Kind = "breadcrumb"
+	KindTransaction Kind = "transaction"
+)
+
+// Record событие в том виде, в котором его сохраняют File, HTTP и Recorder
+type Record struct {
+	Time      time.Time              `json:"time"`
+	Kind      Kind                   `json:"kind"`
+	Level     Level                  `json:"level,omitempty"`
+	Message   string                 `json:"message"`
+	ErrorType string                 `json:"error_type,omitempty"`
+	Err       error                  `json:"-"`
+	Category  string                 `json:"category,omitempty"`
+	Operation string                 `json:"operation,omitempty"`
+	Status    string                 `json:"status,omitempty"`
+	Duration  time.Duration          `json:"duration,omitempty"`
+	Tags      map[string]string      `json:"tags,omitempty"`
+	Extra     map[string]interface{} `json:"extra,omitempty"`
+}
+
+// local реализует Reporter поверх функции записи: события не отправляются по сети
+// сразу, а превращаются в Record
+type local struct {
+	write func(Record)
+}
+
+type scopeKey struct{}
+
+func (l local) CaptureError(ctx context.Context, err error, event Event) {
+	rec := l.record(ctx, KindError, event.Level, LevelError, event.Tags)
+	rec.Message = err.Error()
+	rec.ErrorType = fmt.Sprintf("%T", err)
+	rec.Err = err
+	rec.Extra = redactExtra(event.Extra)
+	l.write(rec)
+}
+
+func (l local) CaptureMessage(ctx context.Context, message string, event Event) {
+	rec := l.record(ctx, KindMessage, event.Level, LevelInfo, event.Tags)
+	rec.Message = message
+	rec.Extra = redactExtra(event.Extra)
+	l.write(rec)
+}
+
+func (l local) AddBreadcrumb(ctx context.Context, breadcrumb Breadcrumb) {
+	rec := l.record(ctx, KindBreadcrumb, breadcrumb.Level, LevelInfo, nil)
+	if !breadcrumb.Timestamp.IsZero() {
+		rec.Time = breadcrumb.Timestamp
+	}
+	rec.Message = breadcrumb.Message
+	rec.Category = breadcrumb.Category
+	rec.Extra = redactExtra(breadcrumb.Data)
+	l.write(rec)
+}
+
+func (l local) StartTransaction(ctx context.Context, name, operation string) (context.Context, Transaction) {
+	return ctx, &localTransaction{
+		local:     l,
+		rec:       l.record(ctx, KindTransaction, LevelInfo, LevelInfo, nil),
+		name:      name,
+		operation: operation,
+	}
+}
+
+func (l local) NewScope(ctx context.Context, s Scope) context.Context {
+	parent, _ := ctx.Value(scopeKey{}).(map[string]string)
+	tags := make(map[string]string, len(parent)+len(s.Tags)+2)
+	for key, value := range parent {
+		tags[key] = value
+	}
+	for key, value := range s.Tags {
+		tags[key] = value
+	}
+	if r := s.Request; r != nil {
+		tags["http.method"] = r.Method
+		tags["http.url"] = r.URL.Path
+	}
+
+	return context.WithValue(ctx, scopeKey{}, tags)
+}
+
+// record создает запись с тегами scope, request ID и тегами события
+func (l local) record(ctx context.Context, kind Kind, level, def Level, tags map[string]string) Record {
+	if level == "" {
+		level = def
+	}
+
+	rec := Record{Time: time.Now(), Kind: kind, Level: level}
+
+	scope, _ := ctx.Value(scopeKey{}).(map[string]string)
+	id, hasID := requestid.FromContext(ctx)
+	if len(scope) == 0 && len(tags) == 0 && !hasID {
+		return rec
+	}
+
+	rec.Tags = make(map[string]string, len(scope)+len(tags)+1)
+	for key, value := range scope {
+		rec.Tags[key] = value
+	}
+	if hasID {
+		rec.Tags["request_id"] = id
+	}
+	for key, value := range tags {
+		rec.Tags[key] = value
+	}
+
+	return rec
+}
+
+func redactExtra(extra map[string]interface{}) map[string]interface{} {
+	if len(extra) == 0 {
+		return nil
+	}
+
+	out := make(map[string]interface{}, len(extra))
+	for key, value := range extra {
+		if err, ok := value.(error); ok {
+			// Ошибки без экспортируемых полей сериализуются в JSON как {}
+			value = err.Error()
+		}
+		out[key] = redact.Value(value)
+	}
+
+	return out
+}
+
+type localTransaction struct {
+	local     local
+	rec       Record
+	name      string
+	operation string
+}
+
+func (t *localTransaction) SetTag(key, value string) {
+	if t.rec.Tags == nil {
+		t.rec.Tags = make(map[string]string)
+	}
+	t.rec.Tags[key] = value
+}
+
+func (t *localTransaction) Finish(err error) {
+	t.rec.Message = t.name
+	t.rec.Operation = t.operation
+	t.rec.Status = spanStatus(err).String()
+	t.rec.Duration = time.Since(t.rec.Time)
+	t.local.write(t.rec)
+}
@@ -0,0 +1,67 @@
+package reporter
+
+import (
+	"sync"
+	"time"
+)
+
+// Recorder сохраняет события в памяти. Используется в тестах, чтобы проверить,
+// что и с какими тегами было отправлено.
+type Recorder struct {
+	local
+
+	mu      sync.Mutex
+	records []Record
+}
+
+// NewRecorder создает пустой Recorder
+func NewRecorder() *Recorder {
+	r := &Recorder{}
+	r.local = local{write: r.write}
+
+	return r
+}
+
+func (r *Recorder) write(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = append(r.records, rec)
+}
+
+// Records возвращает записи в порядке отправки. Если указаны виды, возвращаются только они.
+func (r *Recorder) Records(kinds ...Kind) []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var out []Record
+	for _, rec := range r.records {
+		if len(kinds) == 0 || containsKind(kinds, rec.Kind) {
+			out = append(out, rec)
+		}
+	}
+
+	return out
+}
+
+// Reset удаляет сохраненные записи
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records = nil
+}
+
+// Flush ничего не делает: записи сохраняются сразу
+func (r *Recorder) Flush(time.Duration) bool {
+	return true
+}
+
+func containsKind(kinds []Kind, kind Kind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package reporter
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Level уровень события. Значения совпадают с уровнями Sentry.
+type Level string
+
+const (
+	LevelDebug   Level = "debug"
+	LevelInfo    Level = "info"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+	LevelFatal   Level = "fatal"
+)
+
+// Event дополнительные данные ошибки или сообщения. Значения Extra проходят через
+// redact.Value, поэтому protobuf сообщения попадают в отчет со скрытыми полями.
+type Event struct {
+	Level Level // по умолчанию error для ошибок и info для сообщений
+	Tags  map[string]string
+	Extra map[string]interface{}
+}
+
+// Breadcrumb запись о том, что происходило до ошибки
+type Breadcrumb struct {
+	Category  string
+	Message   string
+	Level     Level
+	Data      map[string]interface{}
+	Timestamp time.Time // пустое значение — текущее время
+}
+
+// Scope данные запроса, которые добавляются ко всем событиям, отправленным с его контекстом
+type Scope struct {
+	Tags    map[string]string
+	Request *http.Request // HTTP запрос без чувствительных заголовков
+}
+
+// Transaction операция, длительность и результат которой отправляются при Finish
+type Transaction interface {
+	SetTag(key, value string)
+	// Finish завершает транзакцию. Статус определяется по коду gRPC ошибки, nil — ok.
+	Finish(err error)
+}
+
+// Reporter отправляет ошибки, сообщения, breadcrumbs и транзакции в систему отслеживания ошибок.
+// Реализации: Sentry (NewSentry), файл JSON lines (NewFile), HTTP (NewHTTP) и
+// запись в память для тестов (NewRecorder).
+type Reporter interface {
+	CaptureError(ctx context.Context, err error, event Event)
+	CaptureMessage(ctx context.Context, message string, event Event)
+	AddBreadcrumb(ctx context.Context, breadcrumb Breadcrumb)
+	// StartTransaction возвращает контекст транзакции, в котором выполняется операция.
+	// Транзакция, начатая в этом контексте, становится частью внешней.
+	StartTransaction(ctx context.Context, name, operation string) (context.Context, Transaction)
+	// NewScope возвращает контекст, события которого не смешиваются с событиями других запросов
+	NewScope(ctx context.Context, scope Scope) context.Context
+	// Flush ждет отправки накопленных событий, false — не успели за timeout
+	Flush(timeout time.Duration) bool
+}
+
+type holder struct {
+	reporter Reporter
+}
+
+var global atomic.Pointer[holder]
+
+func init() {
+	global.Store(&holder{reporter: Nop()})
+}
+
+// Set задает Reporter, который используют функции пакета. nil отключает отправку.
+func Set(r Reporter) {
+	if r == nil {
+		r = Nop()
+	}
+	global.Store(&holder{reporter: r})
+}
+
+// Get возвращает Reporter, заданный через Set. До Set возвращает Reporter, который ничего не отправляет.
+func Get() Reporter {
+	return global.Load().reporter
+}
+
+// CaptureError отправляет ошибку через Reporter по умолчанию
+func CaptureError(ctx context.Context, err error, event Event) {
+	Get().CaptureError(ctx, err, event)
+}
+
+// CaptureMessage отправляет сообщение через Reporter по умолчанию
+func CaptureMessage(ctx context.Context, message string, event Event) {
+	Get().CaptureMessage(ctx, message, event)
+}
+
+// AddBreadcrumb добавляет breadcrumb через Reporter по умолчанию
+func AddBreadcrumb(ctx context.Context, breadcrumb Breadcrumb) {
+	Get().AddBreadcrumb(ctx, breadcrumb)
+}
+
+// StartTransaction начинает транзакцию через Reporter по умолчанию
+func StartTransaction(ctx context.Context, name, operation string) (context.Context, Transaction) {
+	return Get().StartTransaction(ctx, name, operation)
+}
+
+// NewScope создает scope запроса через Reporter по умолчанию
+func NewScope(ctx context.Context, scope Scope) context.Context {
+	return Get().NewScope(ctx, scope)
+}
+
+// Flush ждет отправки событий Reporter по умолчанию
+func Flush(timeout time.Duration) bool {
+	return Get().Flush(timeout)
+}
+
+type nop struct{}
+
+// Nop возвращает Reporter, который ничего не отправляет
+func Nop() Reporter {
+	return nop{}
+}
+
+func (nop) CaptureError(context.Context, error, Event)    {}
+func (nop) CaptureMessage(context.Context, string, Event) {}
+func (nop) AddBreadcrumb(context.Context, Breadcrumb)     {}
+func (nop) Flush(time.Duration) bool                      { return true }
+
+func (nop) StartTransaction(ctx context.Context, _, _ string) (context.Context, Transaction) {
+	return ctx, nopTransaction{}
+}
+
+func (nop) NewScope(ctx context.Context, _ Scope) context.Context {
+	return ctx
+}
+
+type nopTransaction struct{}
+
+func (nopTransaction) SetTag(string, string) {}
+func (nopTransaction) Finish(error)          {}
@@ -0,0 +1,166 @@
+package reporter
+
+import (
+	"context"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/redact"
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+)
+
+type sentryReporter struct{}
+
+// NewSentry возвращает Reporter поверх SDK Sentry. SDK инициализируется отдельно
+// (monitoring.SentryEnterprise). События отправляются в hub из контекста, созданный
+// NewScope, или в копию глобального hub.
+func NewSentry() Reporter {
+	return sentryReporter{}
+}
+
+func (sentryReporter) CaptureError(ctx context.Context, err error, event Event) {
+	hub := SentryHub(ctx)
+	hub.WithScope(func(scope *sentry.Scope) {
+		applyEvent(ctx, scope, event, LevelError)
+		hub.CaptureException(err)
+	})
+}
+
+func (sentryReporter) CaptureMessage(ctx context.Context, message string, event Event) {
+	hub := SentryHub(ctx)
+	hub.WithScope(func(scope *sentry.Scope) {
+		applyEvent(ctx, scope, event, LevelInfo)
+		hub.CaptureMessage(message)
+	})
+}
+
+func (sentryReporter) AddBreadcrumb(ctx context.Context, breadcrumb Breadcrumb) {
+	SentryHub(ctx).AddBreadcrumb(&sentry.Breadcrumb{
+		Category:  breadcrumb.Category,
+		Message:   breadcrumb.Message,
+		Level:     sentry.Level(breadcrumb.Level),
+		Data:      breadcrumb.Data,
+		Timestamp: breadcrumb.Timestamp,
+	}, nil)
+}
+
+func (sentryReporter) StartTransaction(ctx context.Context, name, operation string) (context.Context, Transaction) {
+	// Внутри другой транзакции создается ее дочерний span: sentry.StartTransaction
+	// вернул бы внешнюю транзакцию, и Finish завершил бы ее раньше времени
+	span := sentry.StartSpan(ctx, operation, sentry.WithTransactionName(name), sentry.WithDescription(name))
+	return span.Context(), sentryTransaction{span: span}
+}
+
+func (sentryReporter) NewScope(ctx context.Context, s Scope) context.Context {
+	hub := SentryHub(ctx).Clone()
+	scope := hub.Scope()
+
+	for key, value := range s.Tags {
+		scope.SetTag(key, value)
+	}
+	if id, ok := requestid.FromContext(ctx); ok {
+		scope.SetTag("request_id", id)
+	}
+
+	if r := s.Request; r != nil {
+		// SDK без SendDefaultPII не передает cookie и заголовки авторизации
+		scope.SetRequest(r)
+		scope.SetTag("http.method", r.Method)
+		scope.SetTag("http.url", r.URL.Path)
+		scope.SetTag("http.user_agent", r.UserAgent())
+
+		// Пользователь из заголовков запроса не ставится: клиент может подставить любой
+		if sessionID := r.Header.Get("X-Session-ID"); sessionID != "" {
+			scope.SetTag("session.id", sessionID)
+		}
+	}
+
+	return sentry.SetHubOnContext(ctx, hub)
+}
+
+func (sentryReporter) Flush(timeout time.Duration) bool {
+	return sentry.Flush(timeout)
+}
+
+// applyEvent переносит данные события в scope, который действует только для этого события
+func applyEvent(ctx context.Context, scope *sentry.Scope, event Event, level Level) {
+	if event.Level != "" {
+		level = event.Level
+	}
+	scope.SetLevel(sentry.Level(level))
+
+	if id, ok := requestid.FromContext(ctx); ok {
+		scope.SetTag("request_id", id)
+	}
+	for key, value := range event.Tags {
+		scope.SetTag(key, value)
+	}
+	for key, value := range event.Extra {
+		scope.SetContext(key, sentry.Context{"value": redact.Value(value)})
+	}
+}
+
+// SentryHub возвращает hub запроса, созданный NewScope. Если запрос его не создал, возвращается
+// копия глобального hub, чтобы scope фоновых вызовов не смешивались между собой.
+func SentryHub(ctx context.Context) *sentry.Hub {
+	if hub := sentry.GetHubFromContext(ctx); hub != nil {
+		return hub
+	}
+	return sentry.CurrentHub().Clone()
+}
+
+type sentryTransaction struct {
+	span *sentry.Span
+}
+
+func (t sentryTransaction) SetTag(key, value string) {
+	t.span.SetTag(key, value)
+}
+
+func (t sentryTransaction) Finish(err error) {
+	t.span.Status = spanStatus(err)
+	t.span.Finish()
+}
+
+// spanStatus переводит код ответа gRPC в статус транзакции Sentry
+func spanStatus(err error) sentry.SpanStatus {
+	switch status.Code(err) {
+	case codes.OK:
+		return sentry.SpanStatusOK
+	case codes.Canceled:
+		return sentry.SpanStatusCanceled
+	case codes.Unknown:
+		return sentry.SpanStatusUnknown
+	case codes.InvalidArgument:
+		return sentry.SpanStatusInvalidArgument
+	case codes.DeadlineExceeded:
+		return sentry.SpanStatusDeadlineExceeded
+	case codes.NotFound:
+		return sentry.SpanStatusNotFound
+	case codes.AlreadyExists:
+		return sentry.SpanStatusAlreadyExists
+	case codes.PermissionDenied:
+		return sentry.SpanStatusPermissionDenied
+	case codes.ResourceExhausted:
+		return sentry.SpanStatusResourceExhausted
+	case codes.FailedPrecondition:
+		return sentry.SpanStatusFailedPrecondition
+	case codes.Aborted:
+		return sentry.SpanStatusAborted
+	case codes.OutOfRange:
+		return sentry.SpanStatusOutOfRange
+	case codes.Unimplemented:
+		return sentry.SpanStatusUnimplemented
+	case codes.Unavailable:
+		return sentry.SpanStatusUnavailable
+	case codes.DataLoss:
+		return sentry.SpanStatusDataLoss
+	case codes.Unauthenticated:
+		return sentry.SpanStatusUnauthenticated
+	default:
+		return sentry.SpanStatusInternalError
+	}
+}
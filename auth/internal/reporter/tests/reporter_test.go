@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	rec := reporter.NewRecorder()
+	errDB := errors.New("connection refused")
+
+	ctx := requestid.ToContext(context.Background(), "req-1")
+	ctx = rec.NewScope(ctx, reporter.Scope{
+		Tags:    map[string]string{"grpc.method": "/user_v1.UserV1/Get"},
+		Request: httptest.NewRequest(http.MethodGet, "/user/v1/1", nil),
+	})
+
+	rec.AddBreadcrumb(ctx, reporter.Breadcrumb{Category: "db", Message: "query started"})
+	rec.CaptureError(ctx, errDB, reporter.Event{
+		Tags:  map[string]string{"component": "database"},
+		Extra: map[string]interface{}{"cause": errDB},
+	})
+	rec.CaptureMessage(context.Background(), "slow query", reporter.Event{Level: reporter.LevelWarning})
+
+	txCtx, tx := rec.StartTransaction(ctx, "/user_v1.UserV1/Get", "grpc.server")
+	require.Equal(t, ctx, txCtx)
+	tx.SetTag("type", "unary")
+	tx.Finish(status.Error(codes.NotFound, "user not found"))
+
+	require.Len(t, rec.Records(), 4)
+
+	errs := rec.Records(reporter.KindError)
+	require.Len(t, errs, 1)
+	require.ErrorIs(t, errs[0].Err, errDB)
+	require.Equal(t, reporter.LevelError, errs[0].Level)
+	require.Equal(t, "connection refused", errs[0].Message)
+	require.Equal(t, "*errors.errorString", errs[0].ErrorType)
+	require.Equal(t, map[string]string{
+		"request_id":  "req-1",
+		"grpc.method": "/user_v1.UserV1/Get",
+		"http.method": http.MethodGet,
+		"http.url":    "/user/v1/1",
+		"component":   "database",
+	}, errs[0].Tags)
+	require.Equal(t, "connection refused", errs[0].Extra["cause"])
+
+	messages := rec.Records(reporter.KindMessage)
+	require.Len(t, messages, 1)
+	require.Equal(t, reporter.LevelWarning, messages[0].Level)
+	require.Empty(t, messages[0].Tags)
+
+	txs := rec.Records(reporter.KindTransaction)
+	require.Len(t, txs, 1)
+	require.Equal(t, "not_found", txs[0].Status)
+	require.Equal(t, "grpc.server", txs[0].Operation)
+	require.Equal(t, "unary", txs[0].Tags["type"])
+
+	rec.Reset()
+	require.Empty(t, rec.Records())
+}
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "reports", "errors.json")
+	file, err := reporter.NewFile(path)
+	require.NoError(t, err)
+
+	ctx := requestid.ToContext(context.Background(), "req-2")
+	file.CaptureError(ctx, errors.New("boom"), reporter.Event{Tags: map[string]string{"error_type": "panic"}})
+	file.AddBreadcrumb(ctx, reporter.Breadcrumb{Message: "step"})
+	require.True(t, file.Flush(time.Second))
+	require.NoError(t, file.Close())
+
+	records := readRecords(t, path)
+	require.Len(t, records, 2)
+	require.Equal(t, reporter.KindError, records[0].Kind)
+	require.Equal(t, "boom", records[0].Message)
+	require.Equal(t, map[string]string{"request_id": "req-2", "error_type": "panic"}, records[0].Tags)
+	require.Equal(t, reporter.KindBreadcrumb, records[1].Kind)
+}
+
+func TestHTTP(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		received []reporter.Record
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var rec reporter.Record
+		if json.Unmarshal(body, &rec) == nil {
+			mu.Lock()
+			received = append(received, rec)
+			mu.Unlock()
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	h := reporter.NewHTTP(server.URL, time.Second)
+	h.CaptureMessage(context.Background(), "first", reporter.Event{})
+	h.CaptureMessage(context.Background(), "second", reporter.Event{})
+	require.True(t, h.Flush(time.Second))
+
+	mu.Lock()
+	require.Len(t, received, 2)
+	require.Equal(t, "first", received[0].Message)
+	require.Equal(t, reporter.LevelInfo, received[0].Level)
+	mu.Unlock()
+
+	require.NoError(t, h.Close())
+	// После Close события отбрасываются без паники
+	h.CaptureMessage(context.Background(), "late", reporter.Event{})
+	require.False(t, h.Flush(10*time.Millisecond))
+}
+
+func TestDefaultReporter(t *testing.T) {
+	rec := reporter.NewRecorder()
+	reporter.Set(rec)
+	t.Cleanup(func() { reporter.Set(nil) })
+
+	reporter.CaptureError(context.Background(), errors.New("boom"), reporter.Event{})
+	require.Len(t, rec.Records(reporter.KindError), 1)
+
+	reporter.Set(nil)
+	reporter.CaptureError(context.Background(), errors.New("boom"), reporter.Event{})
+	require.Len(t, rec.Records(reporter.KindError), 1)
+	require.True(t, reporter.Flush(time.Second))
+}
+
+func readRecords(t *testing.T, path string) []reporter.Record {
+	t.Helper()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []reporter.Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec reporter.Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &rec))
+		records = append(records, rec)
+	}
+	require.NoError(t, scanner.Err())
+
+	return records
+}
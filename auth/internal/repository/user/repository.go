@@ -2,8 +2,10 @@ package user
 
 import (
 	"context"
+	"errors"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
 
 	"github.com/MercerMorning/go_example/auth/internal/client/db"
 	"github.com/MercerMorning/go_example/auth/internal/model"
@@ -77,6 +79,9 @@ func (r *repo) Get(ctx context.Context, id int64) (*model.User, error) {
 
 	var user modelRepo.User
 	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(&user.ID, &user.Info.Name, &user.Info.Email, &user.Info.Password, &user.Info.Role, &user.CreatedAt, &user.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -103,6 +108,9 @@ func (r *repo) GetByEmail(ctx context.Context, email string) (*model.User, error
 
 	var user modelRepo.User
 	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(&user.ID, &user.Info.Name, &user.Info.Email, &user.Info.Password, &user.Info.Role, &user.CreatedAt, &user.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
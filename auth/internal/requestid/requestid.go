@@ -0,0 +1,76 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// Header HTTP заголовок с идентификатором запроса
+	Header = "X-Request-ID"
+	// MetadataKey ключ gRPC метаданных с идентификатором запроса
+	MetadataKey = "x-request-id"
+
+	maxLength = 128
+)
+
+type requestIDKey struct{}
+
+// New генерирует идентификатор запроса в формате UUID v4
+func New() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Valid проверяет идентификатор, пришедший от клиента: непустой,
+// не длиннее 128 символов и только из печатных ASCII символов
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ToContext возвращает контекст с идентификатором запроса
+func ToContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// FromContext возвращает идентификатор запроса из контекста
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// HTTPMiddleware принимает X-Request-ID клиента или генерирует новый,
+// кладет его в контекст запроса и возвращает в заголовке ответа.
+// Заголовок запроса заменяется итоговым идентификатором, чтобы шлюз передал в gRPC тот же.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(Header)
+		if !Valid(id) {
+			id = New()
+		}
+
+		r.Header.Set(Header, id)
+		w.Header().Set(Header, id)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request_id", id))
+
+		next.ServeHTTP(w, r.WithContext(ToContext(r.Context(), id)))
+	})
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/MercerMorning/go_example/auth/internal/interceptor"
+	"github.com/MercerMorning/go_example/auth/internal/requestid"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var got string
+	handler := requestid.HTTPMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got, _ = requestid.FromContext(r.Context())
+		require.Equal(t, got, r.Header.Get(requestid.Header))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestid.Header, "client-id-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, "client-id-1", got)
+	require.Equal(t, "client-id-1", rec.Header().Get(requestid.Header))
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestid.Header, "bad id with spaces")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.NotEqual(t, "bad id with spaces", got)
+	require.Len(t, got, 36)
+	require.Equal(t, got, rec.Header().Get(requestid.Header))
+}
+
+func TestRequestIDInterceptor(t *testing.T) {
+	t.Parallel()
+
+	stream := &transportStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(requestid.MetadataKey, "client-id-2"))
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	_, err := interceptor.RequestIDInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test/Method"},
+		func(ctx context.Context, _ interface{}) (interface{}, error) {
+			return nil, interceptor.ClientRequestIDInterceptor(ctx, "/other/Method", nil, nil, nil, invoker)
+		})
+	require.NoError(t, err)
+	require.Equal(t, []string{"client-id-2"}, stream.header.Get(requestid.MetadataKey))
+	require.Equal(t, []string{"client-id-2"}, outgoing.Get(requestid.MetadataKey))
+}
+
+type transportStream struct {
+	header metadata.MD
+}
+
+func (s *transportStream) Method() string { return "/test/Method" }
+
+func (s *transportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *transportStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+
+func (s *transportStream) SetTrailer(metadata.MD) error { return nil }
@@ -0,0 +1,164 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BaseEnv набор фикстур, который загружается для любого окружения
+const BaseEnv = "base"
+
+// Stats результат загрузки одной секции фикстур
+type Stats struct {
+	Created int
+	Skipped int
+}
+
+// EntitySeeder загружает одну секцию файла фикстур (например, "users").
+// Загрузка должна быть идемпотентной: повторный запуск не создает дубликатов.
+type EntitySeeder interface {
+	Seed(ctx context.Context, section *yaml.Node) (Stats, error)
+}
+
+// Seeder загружает фикстуры из YAML/JSON файлов, передавая каждую секцию
+// зарегистрированному EntitySeeder
+type Seeder struct {
+	seeders map[string]EntitySeeder
+	order   []string
+}
+
+// New создает Seeder без зарегистрированных сущностей
+func New() *Seeder {
+	return &Seeder{
+		seeders: make(map[string]EntitySeeder),
+	}
+}
+
+// Register регистрирует загрузчик секции. Секции загружаются в порядке регистрации,
+// поэтому сущности, от которых зависят другие, нужно регистрировать первыми.
+func (s *Seeder) Register(section string, seeder EntitySeeder) {
+	if _, ok := s.seeders[section]; !ok {
+		s.order = append(s.order, section)
+	}
+	s.seeders[section] = seeder
+}
+
+// LoadEnv загружает фикстуры из dir/base и dir/<env>
+func (s *Seeder) LoadEnv(ctx context.Context, dir, env string) (map[string]Stats, error) {
+	envs := []string{BaseEnv}
+	if env != "" && env != BaseEnv {
+		envs = append(envs, env)
+	}
+
+	var files []string
+	for _, e := range envs {
+		envDir := filepath.Join(dir, e)
+		if _, err := os.Stat(envDir); os.IsNotExist(err) {
+			log.Printf("Fixture set %s not found, skipping", envDir)
+			continue
+		}
+
+		envFiles, err := fixtureFiles(envDir)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, envFiles...)
+	}
+
+	return s.LoadFiles(ctx, files...)
+}
+
+// LoadFiles загружает фикстуры из перечисленных файлов
+func (s *Seeder) LoadFiles(ctx context.Context, files ...string) (map[string]Stats, error) {
+	total := make(map[string]Stats)
+
+	for _, file := range files {
+		sections, err := readFixtureFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		for name := range sections {
+			if _, ok := s.seeders[name]; !ok {
+				return nil, fmt.Errorf("%s: unknown fixture section %q", file, name)
+			}
+		}
+
+		for _, name := range s.order {
+			section, ok := sections[name]
+			if !ok {
+				continue
+			}
+
+			stats, err := s.seeders[name].Seed(ctx, section)
+			if err != nil {
+				return nil, fmt.Errorf("%s: failed to seed %s: %w", file, name, err)
+			}
+			log.Printf("%s: %s created=%d skipped=%d", file, name, stats.Created, stats.Skipped)
+
+			t := total[name]
+			t.Created += stats.Created
+			t.Skipped += stats.Skipped
+			total[name] = t
+		}
+	}
+
+	return total, nil
+}
+
+func fixtureFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures directory %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
+
+// readFixtureFile читает файл фикстур. JSON является подмножеством YAML,
+// поэтому оба формата разбираются одним декодером.
+func readFixtureFile(file string) (map[string]*yaml.Node, error) {
+	body, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err = yaml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("%s: invalid fixture file: %w", file, err)
+	}
+
+	sections := make(map[string]*yaml.Node)
+	if len(doc.Content) == 0 {
+		return sections, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: fixture file must be a mapping of sections", file)
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		sections[root.Content[i].Value] = root.Content[i+1]
+	}
+
+	return sections, nil
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gojuno/minimock/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/MercerMorning/go_example/auth/internal/model"
+	"github.com/MercerMorning/go_example/auth/internal/seed"
+
+	serviceMocks "github.com/MercerMorning/go_example/auth/internal/service/mocks"
+)
+
+func TestLoadEnv(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx = context.Background()
+		mc  = minimock.NewController(t)
+		dir = t.TempDir()
+	)
+
+	writeFixture(t, filepath.Join(dir, "base", "users.yaml"), `
+users:
+  - name: Admin
+    email: admin@example.com
+    password: admin-password
+    role: admin
+`)
+	writeFixture(t, filepath.Join(dir, "local", "users.json"), `
+{"users": [{"name": "Alice", "email": "alice@example.com", "password": "alice-password"}]}
+`)
+	writeFixture(t, filepath.Join(dir, "staging", "users.yaml"), `
+users:
+  - name: Staging
+    email: staging@example.com
+    password: staging-password
+`)
+
+	userServiceMock := serviceMocks.NewUserServiceMock(mc)
+	userServiceMock.GetByEmailMock.Set(func(_ context.Context, email string) (*model.User, error) {
+		if email == "admin@example.com" {
+			return &model.User{ID: 1}, nil
+		}
+		return nil, model.ErrUserNotFound
+	})
+	userServiceMock.CreateMock.Expect(ctx, &model.UserInfo{
+		Name:     "Alice",
+		Email:    "alice@example.com",
+		Password: "alice-password",
+		Role:     "USER",
+	}).Return(2, nil)
+
+	seeder := seed.New()
+	seeder.Register("users", seed.NewUserSeeder(userServiceMock))
+
+	stats, err := seeder.LoadEnv(ctx, dir, "local")
+	require.NoError(t, err)
+	require.Equal(t, seed.Stats{Created: 1, Skipped: 1}, stats["users"])
+}
+
+func TestLoadFilesUnknownSection(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "orders.yaml")
+	writeFixture(t, file, "orders: []\n")
+
+	_, err := seed.New().LoadFiles(context.Background(), file)
+	require.ErrorContains(t, err, `unknown fixture section "orders"`)
+}
+
+func writeFixture(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
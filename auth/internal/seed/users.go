@@ -0,0 +1,155 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"gopkg.in/yaml.v3"
+
+	"github.com/MercerMorning/go_example/auth/internal/model"
+	"github.com/MercerMorning/go_example/auth/internal/service"
+	"github.com/MercerMorning/go_example/auth/internal/utils"
+	desc "github.com/MercerMorning/go_example/auth/pkg/user_v1"
+)
+
+// UserFixture пользователь в файле фикстур
+type UserFixture struct {
+	Name     string `yaml:"name"`
+	Email    string `yaml:"email"`
+	Password string `yaml:"password"`
+	Role     string `yaml:"role"`
+}
+
+// UserSeeder создает пользователей через UserService, поэтому пароли хешируются
+// так же, как при регистрации. Пользователь с уже существующим email пропускается.
+type UserSeeder struct {
+	userService service.UserService
+}
+
+// NewUserSeeder создает загрузчик секции users
+func NewUserSeeder(userService service.UserService) *UserSeeder {
+	return &UserSeeder{
+		userService: userService,
+	}
+}
+
+// Seed загружает секцию users файла фикстур
+func (s *UserSeeder) Seed(ctx context.Context, section *yaml.Node) (Stats, error) {
+	var users []UserFixture
+	if err := section.Decode(&users); err != nil {
+		return Stats{}, fmt.Errorf("invalid users section: %w", err)
+	}
+
+	var stats Stats
+	for _, u := range users {
+		created, err := s.seedUser(ctx, u)
+		if err != nil {
+			return stats, err
+		}
+		if created {
+			stats.Created++
+		} else {
+			stats.Skipped++
+		}
+	}
+
+	return stats, nil
+}
+
+// Generate создает n синтетических пользователей для нагрузочного тестирования.
+// Email строится из номера пользователя, поэтому повторный запуск не создает дубликатов.
+func (s *UserSeeder) Generate(ctx context.Context, n, workers int, password string) (Stats, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	var (
+		mu       sync.Mutex
+		stats    Stats
+		firstErr error
+		wg       sync.WaitGroup
+		jobs     = make(chan int)
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				created, err := s.seedUser(ctx, UserFixture{
+					Name:     gofakeit.Name(),
+					Email:    fmt.Sprintf("loadtest-%06d@example.com", i),
+					Password: password,
+					Role:     desc.Role_USER.String(),
+				})
+
+				mu.Lock()
+				switch {
+				case err != nil && firstErr == nil:
+					firstErr = err
+				case created:
+					stats.Created++
+				case err == nil:
+					stats.Skipped++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := 1; i <= n; i++ {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed || ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+
+	return stats, firstErr
+}
+
+func (s *UserSeeder) seedUser(ctx context.Context, u UserFixture) (bool, error) {
+	role := strings.ToUpper(u.Role)
+	if role == "" {
+		role = desc.Role_USER.String()
+	}
+	if _, ok := desc.Role_value[role]; !ok {
+		return false, fmt.Errorf("user %s: unknown role %q", u.Email, u.Role)
+	}
+
+	if err := utils.ValidateUserInfo(u.Name, u.Email, u.Password, u.Password); err != nil {
+		return false, fmt.Errorf("user %s: %w", u.Email, err)
+	}
+
+	_, err := s.userService.GetByEmail(ctx, u.Email)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, model.ErrUserNotFound) {
+		return false, fmt.Errorf("user %s: %w", u.Email, err)
+	}
+
+	_, err = s.userService.Create(ctx, &model.UserInfo{
+		Name:     u.Name,
+		Email:    u.Email,
+		Password: u.Password,
+		Role:     role,
+	})
+	if err != nil {
+		return false, fmt.Errorf("user %s: %w", u.Email, err)
+	}
+
+	return true, nil
+}
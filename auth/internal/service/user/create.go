@@ -3,14 +3,27 @@ package user
 import (
 	"context"
 
+	"go.uber.org/zap"
+
+	"github.com/MercerMorning/go_example/auth/internal/logger"
 	"github.com/MercerMorning/go_example/auth/internal/model"
+	"github.com/MercerMorning/go_example/auth/internal/utils"
 )
 
 func (s *serv) Create(ctx context.Context, info *model.UserInfo) (int64, error) {
+	hashedPassword, err := utils.HashPassword(info.Password)
+	if err != nil {
+		return 0, err
+	}
+
+	// Не изменяем переданную структуру, в репозиторий уходит только хеш пароля
+	hashedInfo := *info
+	hashedInfo.Password = hashedPassword
+
 	var id int64
-	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+	err = s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
 		var errTx error
-		id, errTx = s.userRepository.Create(ctx, info)
+		id, errTx = s.userRepository.Create(ctx, &hashedInfo)
 		if errTx != nil {
 			return errTx
 		}
@@ -27,5 +40,7 @@ func (s *serv) Create(ctx context.Context, info *model.UserInfo) (int64, error)
 		return 0, err
 	}
 
+	logger.FromContext(ctx).Info("User created", zap.Int64("id", id))
+
 	return id, nil
 }
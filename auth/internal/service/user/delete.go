@@ -2,18 +2,21 @@ package user
 
 import (
 	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/MercerMorning/go_example/auth/internal/logger"
 )
 
 func (s *serv) Delete(ctx context.Context, id int64) error {
-	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
 		return s.userRepository.Delete(ctx, id)
 	})
-}
-
-
-
-
-
-
+	if err != nil {
+		return err
+	}
 
+	logger.FromContext(ctx).Info("User deleted", zap.Int64("id", id))
 
+	return nil
+}
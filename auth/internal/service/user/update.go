@@ -3,19 +3,21 @@ package user
 import (
 	"context"
 
+	"go.uber.org/zap"
+
+	"github.com/MercerMorning/go_example/auth/internal/logger"
 	"github.com/MercerMorning/go_example/auth/internal/model"
 )
 
 func (s *serv) Update(ctx context.Context, id int64, info *model.UserUpdate) error {
-	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
 		return s.userRepository.Update(ctx, id, info)
 	})
-}
-
-
-
-
-
-
+	if err != nil {
+		return err
+	}
 
+	logger.FromContext(ctx).Info("User updated", zap.Int64("id", id))
 
+	return nil
+}
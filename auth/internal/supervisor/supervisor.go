@@ -0,0 +1,90 @@
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/reporter"
+)
+
+// Component долгоживущая часть сервиса: сервер или фоновая задача.
+// Должна завершаться после отмены ctx.
+type Component func(ctx context.Context) error
+
+// Supervisor запускает компоненты в отдельных горутинах по образцу errgroup:
+// ошибка или паника любого компонента отменяет общий контекст, а Wait
+// возвращает первую ошибку после завершения всех компонентов.
+type Supervisor struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	once sync.Once
+	err  error
+}
+
+// New создает Supervisor и контекст, который отменяется при первой ошибке компонента
+func New(ctx context.Context) (*Supervisor, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Supervisor{ctx: ctx, cancel: cancel}, ctx
+}
+
+// Go запускает компонент с контекстом Supervisor. Ошибка компонента дополняется его именем.
+func (s *Supervisor) Go(name string, run Component) {
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		err := s.run(s.ctx, name, run)
+		if err != nil {
+			s.fail(fmt.Errorf("%s: %w", name, err))
+			return
+		}
+
+		logger.Debug("Component stopped", zap.String("component", name))
+	}()
+}
+
+func (s *Supervisor) run(ctx context.Context, name string, run Component) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := string(debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+
+			logger.Error("Component panicked",
+				zap.String("component", name),
+				zap.Any("panic", r),
+				zap.String("stack", stack),
+				logger.Reported(),
+			)
+			reporter.CaptureError(ctx, err, reporter.Event{
+				Tags:  map[string]string{"component": name, "error_type": "panic"},
+				Extra: map[string]interface{}{"stack": stack},
+			})
+		}
+	}()
+
+	return run(ctx)
+}
+
+func (s *Supervisor) fail(err error) {
+	s.once.Do(func() {
+		s.err = err
+		logger.Error("Component failed, stopping", zap.Error(err))
+		s.cancel()
+	})
+}
+
+// Wait дожидается завершения всех компонентов и возвращает первую ошибку
+func (s *Supervisor) Wait() error {
+	s.wg.Wait()
+	s.cancel()
+
+	return s.err
+}
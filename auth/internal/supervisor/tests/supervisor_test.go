@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/supervisor"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(zapcore.NewNopCore())
+	os.Exit(m.Run())
+}
+
+func TestFirstErrorCancelsOthers(t *testing.T) {
+	t.Parallel()
+
+	errListen := errors.New("address already in use")
+
+	sv, ctx := supervisor.New(context.Background())
+	sv.Go("grpc_server", func(context.Context) error {
+		return errListen
+	})
+	sv.Go("http_server", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	sv.Go("shutdown", func(ctx context.Context) error {
+		<-ctx.Done()
+		return errors.New("closed with errors")
+	})
+
+	err := sv.Wait()
+	require.ErrorIs(t, err, errListen)
+	require.EqualError(t, err, "grpc_server: address already in use")
+	require.Error(t, ctx.Err())
+}
+
+func TestPanicBecomesError(t *testing.T) {
+	t.Parallel()
+
+	sv, _ := supervisor.New(context.Background())
+	sv.Go("worker", func(context.Context) error {
+		panic("boom")
+	})
+	sv.Go("reloader", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	require.EqualError(t, sv.Wait(), "worker: panic: boom")
+}
+
+func TestWaitWithoutErrors(t *testing.T) {
+	t.Parallel()
+
+	parent, cancel := context.WithCancel(context.Background())
+	sv, _ := supervisor.New(parent)
+	sv.Go("health", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	cancel()
+	require.NoError(t, sv.Wait())
+}
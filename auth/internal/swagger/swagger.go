@@ -0,0 +1,155 @@
+package swagger
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	openapi "github.com/MercerMorning/go_example/auth/pkg/swagger"
+)
+
+// Prefix путь, под которым отдается документация
+const Prefix = "/swagger/"
+
+var (
+	//go:embed index.html
+	indexHTML string
+	//go:embed init.js
+	initJS []byte
+
+	indexTemplate = template.Must(template.New("index").Parse(indexHTML))
+)
+
+type handler struct {
+	spec      map[string]json.RawMessage
+	apiHost   string
+	apiScheme string
+	basePath  string
+	index     []byte
+	csp       string
+}
+
+// Handler отдает Swagger UI по Prefix и OpenAPI документ по Prefix+"api.swagger.json".
+// В документе host, basePath и schemes подставляются при каждом запросе: host из
+// cfg.APIHost, затем apiHost, затем из запроса; схема из apiScheme или из запроса.
+// apiHost и apiScheme задаются, когда документация отдается не сервером API.
+func Handler(cfg config.Swagger, apiHost, apiScheme string) (http.Handler, error) {
+	var spec map[string]json.RawMessage
+	err := json.Unmarshal(openapi.Spec, &spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+
+	var index bytes.Buffer
+	err = indexTemplate.Execute(&index, struct{ AssetsURL string }{strings.TrimSuffix(cfg.AssetsURL, "/")})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render Swagger UI: %w", err)
+	}
+
+	if cfg.APIHost != "" {
+		apiHost = cfg.APIHost
+	}
+
+	return &handler{
+		spec:      spec,
+		apiHost:   apiHost,
+		apiScheme: apiScheme,
+		basePath:  cfg.BasePath,
+		index:     index.Bytes(),
+		csp:       contentSecurityPolicy(cfg.AssetsURL, apiHost, apiScheme),
+	}, nil
+}
+
+// contentSecurityPolicy разрешает странице Swagger UI загружать скрипты и стили только
+// с этого сервера и из assetsURL, а запросы «Try it out» отправлять только к API
+func contentSecurityPolicy(assetsURL, apiHost, apiScheme string) string {
+	assets := "'self'"
+	if u, err := url.Parse(assetsURL); err == nil && u.Host != "" {
+		assets += " " + u.Scheme + "://" + u.Host
+	}
+
+	connect := "'self'"
+	if apiHost != "" && apiScheme != "" {
+		connect += " " + apiScheme + "://" + apiHost
+	} else if apiHost != "" {
+		connect += " " + apiHost
+	}
+
+	return "default-src 'none'; " +
+		"script-src " + assets + "; " +
+		"style-src " + assets + " 'unsafe-inline'; " +
+		"img-src " + assets + " data:; " +
+		"connect-src " + connect + "; " +
+		"frame-ancestors 'none'"
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch strings.TrimPrefix(r.URL.Path, Prefix) {
+	case "", "index.html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Security-Policy", h.csp)
+		_, _ = w.Write(h.index)
+	case "init.js":
+		w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		_, _ = w.Write(initJS)
+	case "api.swagger.json":
+		h.serveSpec(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *handler) serveSpec(w http.ResponseWriter, r *http.Request) {
+	host, scheme := h.apiHost, h.apiScheme
+	if host == "" {
+		host = forwarded(r, "X-Forwarded-Host", r.Host)
+	}
+	if scheme == "" {
+		scheme = "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		scheme = forwarded(r, "X-Forwarded-Proto", scheme)
+	}
+
+	spec := make(map[string]any, len(h.spec)+3)
+	for k, v := range h.spec {
+		spec[k] = v
+	}
+	spec["host"] = host
+	spec["schemes"] = []string{scheme}
+	if h.basePath != "" {
+		spec["basePath"] = h.basePath
+	}
+
+	body, err := json.Marshal(spec)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// forwarded возвращает первое значение заголовка прокси или def
+func forwarded(r *http.Request, header, def string) string {
+	v, _, _ := strings.Cut(r.Header.Get(header), ",")
+	if v = strings.TrimSpace(v); v != "" {
+		return v
+	}
+
+	return def
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/swagger"
+)
+
+func TestHandlerRewritesSpec(t *testing.T) {
+	t.Parallel()
+
+	handler, err := swagger.Handler(config.Swagger{BasePath: "/auth", AssetsURL: "https://cdn.example.com/ui/"}, "", "")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger/api.swagger.json", nil)
+	req.Host = "api.local:8083"
+	spec := serveSpec(t, handler, req)
+	require.Equal(t, "api.local:8083", spec["host"])
+	require.Equal(t, "/auth", spec["basePath"])
+	require.Equal(t, []any{"http"}, spec["schemes"])
+	require.Contains(t, spec["paths"], "/user/v1/{id}")
+
+	req = httptest.NewRequest(http.MethodGet, "/swagger/api.swagger.json", nil)
+	req.Header.Set("X-Forwarded-Host", "auth.example.com")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	spec = serveSpec(t, handler, req)
+	require.Equal(t, "auth.example.com", spec["host"])
+	require.Equal(t, []any{"https"}, spec["schemes"])
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `src="https://cdn.example.com/ui/swagger-ui-bundle.js"`)
+	require.Contains(t, rec.Body.String(), `src="init.js"`)
+	require.Equal(t, "default-src 'none'; script-src 'self' https://cdn.example.com; style-src 'self' https://cdn.example.com 'unsafe-inline'; "+
+		"img-src 'self' https://cdn.example.com data:; connect-src 'self'; frame-ancestors 'none'", rec.Header().Get("Content-Security-Policy"))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/swagger/missing", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandlerUsesConfiguredAPIHost(t *testing.T) {
+	t.Parallel()
+
+	handler, err := swagger.Handler(config.Swagger{AssetsURL: "https://cdn.example.com"}, "localhost:8083", "https")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger/api.swagger.json", nil)
+	req.Host = "localhost:8090"
+	spec := serveSpec(t, handler, req)
+	require.Equal(t, "localhost:8083", spec["host"])
+	require.Equal(t, []any{"https"}, spec["schemes"])
+	require.NotContains(t, spec, "basePath")
+
+	handler, err = swagger.Handler(config.Swagger{APIHost: "auth.example.com:443", AssetsURL: "https://cdn.example.com"}, "localhost:8083", "https")
+	require.NoError(t, err)
+	spec = serveSpec(t, handler, httptest.NewRequest(http.MethodGet, "/swagger/api.swagger.json", nil))
+	require.Equal(t, "auth.example.com:443", spec["host"])
+}
+
+func serveSpec(t *testing.T, handler http.Handler, req *http.Request) map[string]any {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &spec))
+
+	return spec
+}
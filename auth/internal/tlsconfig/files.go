@@ -0,0 +1,132 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+)
+
+// watched набор файлов, который разбирается заново, когда меняется время
+// изменения или размер любого из них. Файлы проверяются не чаще interval.
+// Если новые файлы не разбираются (например, ключ уже заменен, а сертификат еще нет),
+// остается последнее удачно загруженное значение.
+type watched[T any] struct {
+	files    []string
+	interval time.Duration
+	parse    func() (T, error)
+
+	mu      sync.Mutex
+	value   T
+	stamp   string
+	checked time.Time
+}
+
+func newWatched[T any](interval time.Duration, parse func() (T, error), files ...string) (*watched[T], error) {
+	w := &watched[T]{files: files, interval: interval, parse: parse}
+
+	stamp, err := w.fileStamp()
+	if err != nil {
+		return nil, err
+	}
+	if w.value, err = parse(); err != nil {
+		return nil, err
+	}
+	w.stamp = stamp
+	w.checked = time.Now()
+
+	return w, nil
+}
+
+// get возвращает текущее значение, при необходимости перечитывая файлы
+func (w *watched[T]) get() T {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if time.Since(w.checked) < w.interval {
+		return w.value
+	}
+	w.checked = time.Now()
+
+	w.reload(false)
+
+	return w.value
+}
+
+// Reload перечитывает файлы независимо от интервала и времени изменения
+func (w *watched[T]) Reload() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.checked = time.Now()
+	w.reload(true)
+}
+
+func (w *watched[T]) reload(force bool) {
+	stamp, err := w.fileStamp()
+	if err != nil {
+		logger.Error("Failed to check certificate files", zap.Strings("files", w.files), zap.Error(err))
+		return
+	}
+	if stamp == w.stamp && !force {
+		return
+	}
+
+	value, err := w.parse()
+	if err != nil {
+		logger.Error("Failed to reload certificate files, keeping the previous ones",
+			zap.Strings("files", w.files), zap.Error(err))
+		return
+	}
+
+	if stamp != w.stamp {
+		logger.Info("Certificate files reloaded", zap.Strings("files", w.files))
+	}
+	w.value = value
+	w.stamp = stamp
+}
+
+func (w *watched[T]) fileStamp() (string, error) {
+	var stamp string
+	for _, file := range w.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		stamp += fmt.Sprintf("%s:%d:%d;", file, info.ModTime().UnixNano(), info.Size())
+	}
+
+	return stamp, nil
+}
+
+func loadKeyPair(certFile, keyFile string, interval time.Duration) (*watched[*tls.Certificate], error) {
+	return newWatched(interval, func() (*tls.Certificate, error) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key pair %s, %s: %w", certFile, keyFile, err)
+		}
+		return &cert, nil
+	}, certFile, keyFile)
+}
+
+func loadCAPool(caFile string, interval time.Duration) (*watched[*x509.CertPool], error) {
+	return newWatched(interval, func() (*x509.CertPool, error) {
+		raw, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(raw) {
+			return nil, errors.New("no certificates found in CA file " + caFile)
+		}
+		return pool, nil
+	}, caFile)
+}
@@ -0,0 +1,74 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Identity субъект проверенного клиентского сертификата
+type Identity struct {
+	CommonName   string
+	DNSNames     []string
+	URIs         []string
+	SerialNumber string
+}
+
+// String возвращает URI SAN (например, SPIFFE ID), если он есть, иначе CN
+func (i Identity) String() string {
+	if len(i.URIs) > 0 {
+		return i.URIs[0]
+	}
+
+	return i.CommonName
+}
+
+// PeerIdentity возвращает субъект клиентского сертификата gRPC вызова.
+// Возвращает false, если соединение без TLS или клиент не предъявил проверенный сертификат.
+func PeerIdentity(ctx context.Context) (Identity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, false
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return Identity{}, false
+	}
+
+	return fromState(info.State)
+}
+
+// RequestIdentity возвращает субъект клиентского сертификата HTTP запроса
+func RequestIdentity(r *http.Request) (Identity, bool) {
+	if r.TLS == nil {
+		return Identity{}, false
+	}
+
+	return fromState(*r.TLS)
+}
+
+func fromState(state tls.ConnectionState) (Identity, bool) {
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return Identity{}, false
+	}
+
+	return fromCertificate(state.VerifiedChains[0][0]), true
+}
+
+func fromCertificate(cert *x509.Certificate) Identity {
+	id := Identity{
+		CommonName:   cert.Subject.CommonName,
+		DNSNames:     cert.DNSNames,
+		SerialNumber: cert.SerialNumber.String(),
+	}
+	for _, uri := range cert.URIs {
+		id.URIs = append(id.URIs, uri.String())
+	}
+
+	return id
+}
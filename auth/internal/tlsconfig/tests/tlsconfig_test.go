@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/logger"
+	"github.com/MercerMorning/go_example/auth/internal/tlsconfig"
+)
+
+func TestMain(m *testing.M) {
+	logger.Init(zapcore.NewNopCore())
+	os.Exit(m.Run())
+}
+
+func TestMutualTLSPeerIdentity(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCA(t)
+	ca.issue(t, dir, "server", &x509.Certificate{DNSNames: []string{"localhost"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}})
+	ca.issue(t, dir, "client", &x509.Certificate{Subject: pkix.Name{CommonName: "gateway"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	ca.write(t, dir)
+
+	store := tlsconfig.NewStore(time.Hour)
+	identities := make(chan tlsconfig.Identity, 1)
+	lis := serve(t, store, config.ServerTLS{
+		Enabled:      true,
+		CertFile:     filepath.Join(dir, "server.crt"),
+		KeyFile:      filepath.Join(dir, "server.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+		ClientAuth:   "require",
+	}, identities)
+
+	err := check(t, store, lis, config.ClientTLS{
+		Enabled:    true,
+		CAFile:     filepath.Join(dir, "ca.crt"),
+		CertFile:   filepath.Join(dir, "client.crt"),
+		KeyFile:    filepath.Join(dir, "client.key"),
+		ServerName: "localhost",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "gateway", (<-identities).String())
+
+	// Без клиентского сертификата сервер разрывает соединение
+	err = check(t, store, lis, config.ClientTLS{
+		Enabled:    true,
+		CAFile:     filepath.Join(dir, "ca.crt"),
+		ServerName: "localhost",
+	})
+	require.Error(t, err)
+
+	// Сертификат сервера не выдан на это имя
+	err = check(t, store, lis, config.ClientTLS{
+		Enabled:    true,
+		CAFile:     filepath.Join(dir, "ca.crt"),
+		CertFile:   filepath.Join(dir, "client.crt"),
+		KeyFile:    filepath.Join(dir, "client.key"),
+		ServerName: "auth.example.com",
+	})
+	require.Error(t, err)
+}
+
+func TestCertificateReload(t *testing.T) {
+	dir := t.TempDir()
+	ca := newCA(t)
+	ca.issue(t, dir, "server", &x509.Certificate{DNSNames: []string{"localhost"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}})
+	ca.write(t, dir)
+
+	store := tlsconfig.NewStore(time.Hour)
+	lis := serve(t, store, config.ServerTLS{
+		Enabled:  true,
+		CertFile: filepath.Join(dir, "server.crt"),
+		KeyFile:  filepath.Join(dir, "server.key"),
+	}, nil)
+	client, err := store.Client(config.ClientTLS{Enabled: true, CAFile: filepath.Join(dir, "ca.crt"), ServerName: "localhost"})
+	require.NoError(t, err)
+	require.NoError(t, dial(t, lis, client))
+
+	// Сертификаты выпущены новым CA: клиент узнает о нем только после перезагрузки файлов
+	rotated := newCA(t)
+	rotated.issue(t, dir, "server", &x509.Certificate{DNSNames: []string{"localhost"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}})
+	rotated.write(t, dir)
+	require.NoError(t, dial(t, lis, client), "files are not checked before the interval")
+
+	require.NoError(t, store.Reload(config.Reloadable{}))
+	require.NoError(t, dial(t, lis, client))
+
+	// Битые файлы не заменяют загруженные сертификаты
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "server.crt"), []byte("broken"), 0o600))
+	require.NoError(t, store.Reload(config.Reloadable{}))
+	require.NoError(t, dial(t, lis, client))
+}
+
+func serve(t *testing.T, store *tlsconfig.Store, cfg config.ServerTLS, identities chan<- tlsconfig.Identity) *bufconn.Listener {
+	tlsConfig, err := store.Server(cfg)
+	require.NoError(t, err)
+
+	server := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if id, ok := tlsconfig.PeerIdentity(ctx); ok && identities != nil {
+				identities <- id
+			}
+			return handler(ctx, req)
+		}),
+	)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	return lis
+}
+
+func check(t *testing.T, store *tlsconfig.Store, lis *bufconn.Listener, cfg config.ClientTLS) error {
+	tlsConfig, err := store.Client(cfg)
+	require.NoError(t, err)
+
+	return dial(t, lis, tlsConfig)
+}
+
+func dial(t *testing.T, lis *bufconn.Listener, tlsConfig *tls.Config) error {
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key, der: der}
+}
+
+// issue выпускает сертификат и пишет name.crt и name.key в dir
+func (ca *testCA) issue(t *testing.T, dir, name string, template *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	writePEM(t, filepath.Join(dir, name+".crt"), "CERTIFICATE", der)
+	writePEM(t, filepath.Join(dir, name+".key"), "EC PRIVATE KEY", keyDER)
+
+	_, err = tls.LoadX509KeyPair(filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key"))
+	require.NoError(t, err)
+}
+
+func (ca *testCA) write(t *testing.T, dir string) {
+	writePEM(t, filepath.Join(dir, "ca.crt"), "CERTIFICATE", ca.der)
+}
+
+func writePEM(t *testing.T, path, typ string, der []byte) {
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600))
+}
@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPMiddleware создает span HTTP запроса и продолжает трейс из заголовка traceparent.
+// Шлюз передает контекст запроса в gRPC вызов, поэтому трейс продолжается и на gRPC сервере.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return "HTTP " + r.Method
+		}),
+	)
+}
+
+// GatewayMiddleware называет span HTTP запроса по совпавшему в шлюзе маршруту: GET /user/v1/{id=*}
+func GatewayMiddleware(next runtime.HandlerFunc) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(r.Method + " " + pattern.String())
+			span.SetAttributes(semconv.HTTPRoute(pattern.String()))
+		}
+
+		next(w, r, pathParams)
+	}
+}
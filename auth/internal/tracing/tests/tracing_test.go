@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
+	"github.com/MercerMorning/go_example/auth/internal/tracing"
+)
+
+func TestFileExporterContinuesIncomingTrace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces", "traces.json")
+
+	shutdown, err := tracing.Init(context.Background(), config.Tracing{
+		ServiceName:    "auth",
+		ServiceVersion: "test",
+		Environment:    "test",
+		Exporter:       "file",
+		FilePath:       path,
+		SampleRatio:    1,
+	})
+	require.NoError(t, err)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	var got trace.SpanContext
+	handler := tracing.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = trace.SpanContextFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/user/v1/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Equal(t, traceID, got.TraceID().String())
+	require.NoError(t, shutdown(context.Background()))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(raw), traceID)
+	require.Contains(t, string(raw), `"Name":"HTTP GET"`)
+}
+
+func TestNoneExporter(t *testing.T) {
+	shutdown, err := tracing.Init(context.Background(), config.Tracing{Exporter: "none"})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}
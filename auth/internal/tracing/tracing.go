@@ -1,23 +1,114 @@
 package tracing
 
 import (
-	"github.com/uber/jaeger-client-go/config"
-	"go.uber.org/zap"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/MercerMorning/go_example/auth/internal/config"
 )
 
-func Init(logger *zap.Logger, serviceName string) {
-	cfg := config.Configuration{
-		Sampler: &config.SamplerConfig{
-			Type:  "const",
-			Param: 1,
-		},
-		Reporter: &config.ReporterConfig{
-			LocalAgentHostPort: "localhost:6831",
-		},
+const instrumentationName = "github.com/MercerMorning/go_example/auth"
+
+// Init настраивает глобальный TracerProvider и W3C propagation (traceparent, baggage).
+// Возвращает функцию, которая отправляет накопленные span и закрывает экспортер.
+func Init(ctx context.Context, cfg config.Tracing) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if cfg.Exporter == "none" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, closeOutput, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	_, err := cfg.InitGlobalTracer(serviceName)
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+		semconv.DeploymentEnvironmentName(cfg.Environment),
+	))
 	if err != nil {
-		logger.Fatal("failed to init tracing", zap.Error(err))
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
 	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		return errors.Join(provider.Shutdown(ctx), closeOutput())
+	}, nil
+}
+
+func newExporter(ctx context.Context, cfg config.Tracing) (sdktrace.SpanExporter, func() error, error) {
+	noop := func() error { return nil }
+
+	switch cfg.Exporter {
+	case "otlp":
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint)}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		return exporter, noop, nil
+	case "stdout":
+		exporter, err := newWriterExporter(os.Stdout)
+		return exporter, noop, err
+	case "file":
+		if err := os.MkdirAll(filepath.Dir(cfg.FilePath), 0o755); err != nil {
+			return nil, nil, fmt.Errorf("failed to create traces directory: %w", err)
+		}
+
+		file, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open traces file: %w", err)
+		}
+
+		exporter, err := newWriterExporter(file)
+		if err != nil {
+			_ = file.Close()
+			return nil, nil, err
+		}
+		return exporter, file.Close, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+}
+
+func newWriterExporter(w io.Writer) (sdktrace.SpanExporter, error) {
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+	}
+
+	return exporter, nil
+}
+
+// Tracer возвращает tracer сервиса из глобального TracerProvider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
 }
@@ -0,0 +1,152 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             v3.21.12
+// source: admin.proto
+
+package admin_v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	AdminV1_GetLogLevel_FullMethodName = "/admin_v1.AdminV1/GetLogLevel"
+	AdminV1_SetLogLevel_FullMethodName = "/admin_v1.AdminV1/SetLogLevel"
+)
+
+// AdminV1Client is the client API for AdminV1 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminV1 служебные методы управления сервисом. Требуют токен с ролью admin.
+type AdminV1Client interface {
+	GetLogLevel(ctx context.Context, in *GetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error)
+	SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error)
+}
+
+type adminV1Client struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminV1Client(cc grpc.ClientConnInterface) AdminV1Client {
+	return &adminV1Client{cc}
+}
+
+func (c *adminV1Client) GetLogLevel(ctx context.Context, in *GetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogLevelResponse)
+	err := c.cc.Invoke(ctx, AdminV1_GetLogLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminV1Client) SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*LogLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogLevelResponse)
+	err := c.cc.Invoke(ctx, AdminV1_SetLogLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminV1Server is the server API for AdminV1 service.
+// All implementations must embed UnimplementedAdminV1Server
+// for forward compatibility
+//
+// AdminV1 служебные методы управления сервисом. Требуют токен с ролью admin.
+type AdminV1Server interface {
+	GetLogLevel(context.Context, *GetLogLevelRequest) (*LogLevelResponse, error)
+	SetLogLevel(context.Context, *SetLogLevelRequest) (*LogLevelResponse, error)
+	mustEmbedUnimplementedAdminV1Server()
+}
+
+// UnimplementedAdminV1Server must be embedded to have forward compatible implementations.
+type UnimplementedAdminV1Server struct {
+}
+
+func (UnimplementedAdminV1Server) GetLogLevel(context.Context, *GetLogLevelRequest) (*LogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogLevel not implemented")
+}
+func (UnimplementedAdminV1Server) SetLogLevel(context.Context, *SetLogLevelRequest) (*LogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (UnimplementedAdminV1Server) mustEmbedUnimplementedAdminV1Server() {}
+
+// UnsafeAdminV1Server may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminV1Server will
+// result in compilation errors.
+type UnsafeAdminV1Server interface {
+	mustEmbedUnimplementedAdminV1Server()
+}
+
+func RegisterAdminV1Server(s grpc.ServiceRegistrar, srv AdminV1Server) {
+	s.RegisterService(&AdminV1_ServiceDesc, srv)
+}
+
+func _AdminV1_GetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminV1Server).GetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminV1_GetLogLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminV1Server).GetLogLevel(ctx, req.(*GetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminV1_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminV1Server).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminV1_SetLogLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminV1Server).SetLogLevel(ctx, req.(*SetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminV1_ServiceDesc is the grpc.ServiceDesc for AdminV1 service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminV1_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "admin_v1.AdminV1",
+	HandlerType: (*AdminV1Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLogLevel",
+			Handler:    _AdminV1_GetLogLevel_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _AdminV1_SetLogLevel_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
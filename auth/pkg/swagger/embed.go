@@ -0,0 +1,8 @@
+package swagger
+
+import _ "embed"
+
+// Spec OpenAPI документ API пользователей
+//
+//go:embed api.swagger.json
+var Spec []byte